@@ -0,0 +1,166 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+var ErrAutoFlushClosed = errors.New("auto flush writer: closed")
+
+// AutoFlushWriter 是会自动 Flush 的 bufio.Writer：后台 goroutine 每隔 idleFlush/2 检查一次，
+// 缓存中有数据并且已经超过 idleFlush 没有写入时就 Flush。
+// 写得频繁时仍然按缓存大小批量写出，写入停下来之后数据最多延迟约 1.5×idleFlush 到达底层的 Writer，
+// 适合日志这类需要缓存、又不希望最后几行一直留在缓存里的场景。
+type AutoFlushWriter struct {
+	mu        sync.Mutex
+	bw        *bufio.Writer
+	lastWrite time.Time
+	closed    bool
+
+	idle time.Duration
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewAutoFlushWriter(w io.Writer, bufSize int, idleFlush time.Duration) *AutoFlushWriter {
+	if idleFlush <= 0 {
+		panic("NewAutoFlushWriter: idleFlush must be positive")
+	}
+	a := &AutoFlushWriter{
+		bw:   bufio.NewWriterSize(w, bufSize),
+		idle: idleFlush,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go a.loop()
+	return a
+}
+
+func (a *AutoFlushWriter) loop() {
+	defer close(a.done)
+	// idle 只有 1ns 时 idle/2 为 0，NewTicker 会 panic
+	interval := a.idle / 2
+	if interval <= 0 {
+		interval = a.idle
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+			a.mu.Lock()
+			if a.bw.Buffered() > 0 && time.Since(a.lastWrite) >= a.idle {
+				// 出错时错误保存在 bufio.Writer 中，下一次 Write 或 Close 会返回
+				a.bw.Flush()
+			}
+			a.mu.Unlock()
+		}
+	}
+}
+
+func (a *AutoFlushWriter) Write(p []byte) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return 0, ErrAutoFlushClosed
+	}
+	a.lastWrite = time.Now()
+	return a.bw.Write(p)
+}
+
+func (a *AutoFlushWriter) Flush() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.bw.Flush()
+}
+
+// Close 停止后台的 goroutine 并 Flush 剩余的数据，不会关闭底层的 Writer
+func (a *AutoFlushWriter) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	a.mu.Unlock()
+
+	close(a.stop)
+	<-a.done
+	return a.Flush()
+}
+
+func TestAutoFlushWriter(t *testing.T) {
+	var buf lockedBuffer
+	const idle = 50 * time.Millisecond
+	w := NewAutoFlushWriter(&buf, 4096, idle)
+	defer w.Close()
+
+	w.Write([]byte("x"))
+	if buf.String() != "" {
+		t.Fatalf("got %q before idle flush, want nothing", buf.String())
+	}
+	time.Sleep(2 * idle)
+	if buf.String() != "x" {
+		t.Errorf("got %q after %v, want %q", buf.String(), 2*idle, "x")
+	}
+}
+
+func TestAutoFlushWriterClose(t *testing.T) {
+	var buf lockedBuffer
+	w := NewAutoFlushWriter(&buf, 4096, time.Hour)
+	w.Write([]byte("Don't panic"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "Don't panic" {
+		t.Errorf("got %q after Close", buf.String())
+	}
+	if _, err := w.Write([]byte("!")); err != ErrAutoFlushClosed {
+		t.Errorf("got err %v, want %v", err, ErrAutoFlushClosed)
+	}
+}
+
+// lockedBuffer 是并发安全的 bytes.Buffer，后台 goroutine 写入的同时测试可以读取
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestAutoFlushWriterIdleFlush(t *testing.T) {
+	for _, idle := range []time.Duration{0, -time.Second} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("idleFlush %v: no panic", idle)
+				}
+			}()
+			NewAutoFlushWriter(io.Discard, 16, idle)
+		}()
+	}
+
+	// 1ns 时检查间隔不能取 idle/2 = 0
+	a := NewAutoFlushWriter(io.Discard, 16, time.Nanosecond)
+	io.WriteString(a, "Go")
+	if err := a.Close(); err != nil {
+		t.Error(err)
+	}
+}
@@ -2,7 +2,9 @@ package test
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"testing"
 )
 
@@ -63,11 +65,55 @@ func TestWriteReset(t *testing.T) {
 
 	w2 := new(Writer2)
 	// 由于 Reset 只是简单的丢弃未被处理的数据，所以已经被写入的数据 cd 丢失了
+	// 修复：Reset 之前先 Flush，见 SafeReset
 	bw.Reset(w2)
 	bw.Write([]byte("ef"))
 	bw.Flush()
 }
 
+// SafeReset 先把缓存中的数据 Flush 到原来的 Writer，再 Reset 到 w。
+// Flush 失败时不会 Reset，缓存中的数据和错误都保留在 bw 中，由调用者决定如何处理
+func SafeReset(bw *bufio.Writer, w io.Writer) error {
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	bw.Reset(w)
+	return nil
+}
+
+func TestWriteResetDataLoss(t *testing.T) {
+	var w1, w2 bytes.Buffer
+	bw := bufio.NewWriterSize(&w1, 2)
+	bw.Write([]byte("ab"))
+	bw.Write([]byte("cd"))
+
+	bw.Reset(&w2)
+	bw.Write([]byte("ef"))
+	bw.Flush()
+
+	// cd 既没有写到 w1 也没有写到 w2
+	if w1.String() != "ab" || w2.String() != "ef" {
+		t.Errorf("got %q and %q, want %q and %q", w1.String(), w2.String(), "ab", "ef")
+	}
+}
+
+func TestSafeReset(t *testing.T) {
+	var w1, w2 bytes.Buffer
+	bw := bufio.NewWriterSize(&w1, 2)
+	bw.Write([]byte("ab"))
+	bw.Write([]byte("cd"))
+
+	if err := SafeReset(bw, &w2); err != nil {
+		t.Fatal(err)
+	}
+	bw.Write([]byte("ef"))
+	bw.Flush()
+
+	if w1.String() != "abcd" || w2.String() != "ef" {
+		t.Errorf("got %q and %q, want %q and %q", w1.String(), w2.String(), "abcd", "ef")
+	}
+}
+
 // 检测缓存中还剩余多少空间
 func TestWriteAvailable(t *testing.T) {
 	w := new(Writer)
@@ -0,0 +1,148 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+var errBrokenWriter = errors.New("broken writer")
+
+// brokenWriter 第一次 Write 成功，之后的 Write 都失败
+type brokenWriter struct {
+	buf   bytes.Buffer
+	calls int
+}
+
+func (w *brokenWriter) Write(p []byte) (int, error) {
+	w.calls++
+	if w.calls > 1 {
+		return 0, errBrokenWriter
+	}
+	return w.buf.Write(p)
+}
+
+// Flush 失败后错误会一直保存在 bufio.Writer 中，之后的 Write、Flush 都直接返回这个错误，
+// 写入的数据不会到达底层的 Writer。
+// 恢复的办法只有 Reset：它会清除错误，但同时丢弃缓存中还没写出去的数据，
+// 所以 Reset 之前需要自己决定这些数据是重写还是放弃。
+func TestFlushError(t *testing.T) {
+	w := new(brokenWriter)
+	bw := bufio.NewWriterSize(w, 4)
+
+	bw.Write([]byte("abcd"))
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	bw.Write([]byte("efgh"))
+	if err := bw.Flush(); err != errBrokenWriter {
+		t.Fatalf("got err %v, want %v", err, errBrokenWriter)
+	}
+
+	// 错误是粘滞的：后续的 Write 什么都不写，直接返回之前的错误
+	n, err := bw.Write([]byte("ij"))
+	if n != 0 || err != errBrokenWriter {
+		t.Errorf("got (%d, %v), want (0, %v)", n, err, errBrokenWriter)
+	}
+	if err := bw.Flush(); err != errBrokenWriter {
+		t.Errorf("got err %v, want %v", err, errBrokenWriter)
+	}
+	if bw.Buffered() != 4 {
+		t.Errorf("got %d buffered bytes, want 4", bw.Buffered())
+	}
+
+	// Flush + Reset：换一个 Writer 继续写，缓存中的 efgh 被丢弃
+	var good bytes.Buffer
+	bw.Reset(&good)
+	bw.Write([]byte("ij"))
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if w.buf.String() != "abcd" || good.String() != "ij" {
+		t.Errorf("got %q and %q, want %q and %q", w.buf.String(), good.String(), "abcd", "ij")
+	}
+}
+
+// RecoverableWriter 与 bufio.Writer 一样缓存写入的数据，Flush 失败时错误同样是粘滞的，
+// 不同之处在于 Reset 只清除错误、替换底层的 Writer，缓存中还没写出去的数据会保留下来，
+// 在下一次 Flush 时写到新的 Writer 中。
+type RecoverableWriter struct {
+	w    io.Writer
+	buf  []byte
+	size int
+	err  error
+}
+
+func NewRecoverableWriter(w io.Writer, size int) *RecoverableWriter {
+	return &RecoverableWriter{w: w, buf: make([]byte, 0, size), size: size}
+}
+
+// Write 把 p 追加到缓存中，缓存满了就 Flush。
+// 即使 Flush 失败，p 也已经在缓存中了，所以返回的 n 总是 len(p)，除非之前已经出错。
+func (r *RecoverableWriter) Write(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	r.buf = append(r.buf, p...)
+	if len(r.buf) >= r.size {
+		return len(p), r.Flush()
+	}
+	return len(p), nil
+}
+
+func (r *RecoverableWriter) Flush() error {
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.buf) == 0 {
+		return nil
+	}
+	n, err := r.w.Write(r.buf)
+	if n < len(r.buf) && err == nil {
+		err = io.ErrShortWrite
+	}
+	// 只丢弃已经写出去的部分
+	r.buf = r.buf[:copy(r.buf, r.buf[n:])]
+	r.err = err
+	return err
+}
+
+// Reset 清除粘滞的错误并把底层的 Writer 换成 w，缓存中的数据保持不变
+func (r *RecoverableWriter) Reset(w io.Writer) {
+	r.w = w
+	r.err = nil
+}
+
+func (r *RecoverableWriter) Buffered() int {
+	return len(r.buf)
+}
+
+func TestRecoverableWriter(t *testing.T) {
+	w := new(brokenWriter)
+	rw := NewRecoverableWriter(w, 4)
+
+	rw.Write([]byte("abcd"))
+	if _, err := rw.Write([]byte("efgh")); err != errBrokenWriter {
+		t.Fatalf("got err %v, want %v", err, errBrokenWriter)
+	}
+	if _, err := rw.Write([]byte("ij")); err != errBrokenWriter {
+		t.Errorf("got err %v, want sticky %v", err, errBrokenWriter)
+	}
+
+	var good bytes.Buffer
+	rw.Reset(&good)
+	rw.Write([]byte("ij"))
+	if err := rw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	// 与 bufio.Writer 不同，efgh 没有丢失
+	if w.buf.String() != "abcd" || good.String() != "efghij" {
+		t.Errorf("got %q and %q, want %q and %q", w.buf.String(), good.String(), "abcd", "efghij")
+	}
+	if rw.Buffered() != 0 {
+		t.Errorf("got %d buffered bytes, want 0", rw.Buffered())
+	}
+}
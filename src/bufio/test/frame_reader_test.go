@@ -0,0 +1,138 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var errInvalidFrame = errors.New("frame reader: invalid frame")
+
+// maxBulkSize 与 Redis 的 proto-max-bulk-len 默认值相同，
+// 长度来自数据流，不加限制时一个恶意的长度就能让 make 分配过大的内存甚至 panic
+const maxBulkSize = 512 << 20
+
+// DelimitedFrameReader 读取 Redis RESP 这类协议的帧：<类型字节><数据>\r\n。
+// '$' 类型（bulk string）的数据部分是长度，真正的数据在下一行，
+// 按长度读取，所以数据中可以包含 "\r\n"。
+type DelimitedFrameReader struct {
+	r *bufio.Reader
+}
+
+func NewDelimitedFrameReader(r io.Reader) *DelimitedFrameReader {
+	return &DelimitedFrameReader{r: bufio.NewReader(r)}
+}
+
+// ReadFrame 读取一帧，返回类型字节和数据（不包含结尾的 "\r\n"）。
+// 长度为 -1 的 bulk string 表示空值，返回的 data 为 nil；长度超过 maxBulkSize 时返回 errInvalidFrame。
+func (f *DelimitedFrameReader) ReadFrame() (typeTag byte, data []byte, err error) {
+	line, err := f.readLine()
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(line) == 0 {
+		return 0, nil, errInvalidFrame
+	}
+	typeTag, data = line[0], line[1:]
+	if typeTag != '$' {
+		return typeTag, data, nil
+	}
+
+	n, err := strconv.Atoi(string(data))
+	if err != nil || n < -1 || n > maxBulkSize {
+		return 0, nil, errInvalidFrame
+	}
+	if n == -1 {
+		return typeTag, nil, nil
+	}
+	data = make([]byte, n+2)
+	if _, err := io.ReadFull(f.r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+	if !bytes.HasSuffix(data, []byte("\r\n")) {
+		return 0, nil, errInvalidFrame
+	}
+	return typeTag, data[:n], nil
+}
+
+// readLine 读取以 "\r\n" 结尾的一行，返回的数据不包含 "\r\n"
+func (f *DelimitedFrameReader) readLine() ([]byte, error) {
+	line, err := f.r.ReadBytes('\n')
+	if err != nil {
+		if err == io.EOF && len(line) > 0 {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, errInvalidFrame
+	}
+	return line[:len(line)-2], nil
+}
+
+func TestDelimitedFrameReader(t *testing.T) {
+	input := "+OK\r\n" +
+		"-ERR unknown command\r\n" +
+		":1000\r\n" +
+		"$12\r\nhello\r\nworld\r\n" +
+		"$0\r\n\r\n" +
+		"$-1\r\n"
+	want := []struct {
+		tag  byte
+		data string
+	}{
+		{'+', "OK"},
+		{'-', "ERR unknown command"},
+		{':', "1000"},
+		{'$', "hello\r\nworld"},
+		{'$', ""},
+		{'$', ""},
+	}
+
+	f := NewDelimitedFrameReader(strings.NewReader(input))
+	for i, w := range want {
+		tag, data, err := f.ReadFrame()
+		if err != nil {
+			t.Fatalf("frame %d: %v", i, err)
+		}
+		if tag != w.tag || string(data) != w.data {
+			t.Errorf("frame %d: got (%c, %q), want (%c, %q)", i, tag, data, w.tag, w.data)
+		}
+	}
+	if _, _, err := f.ReadFrame(); err != io.EOF {
+		t.Errorf("got err %v, want EOF", err)
+	}
+}
+
+func TestDelimitedFrameReaderInvalid(t *testing.T) {
+	for _, input := range []string{"+OK\n", "$5\r\nhello!!", "$x\r\n"} {
+		f := NewDelimitedFrameReader(strings.NewReader(input))
+		if _, _, err := f.ReadFrame(); err == nil {
+			t.Errorf("%q: got nil error", input)
+		}
+	}
+	f := NewDelimitedFrameReader(strings.NewReader("$10\r\nshort\r\n"))
+	if _, _, err := f.ReadFrame(); err != io.ErrUnexpectedEOF {
+		t.Errorf("got err %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestDelimitedFrameReaderHostileLength(t *testing.T) {
+	// 长度在读取数据之前就要检查，n+2 溢出或者过大的分配都不能发生
+	for _, input := range []string{
+		"$9223372036854775807\r\n",
+		"$" + strconv.Itoa(maxBulkSize+1) + "\r\nx\r\n",
+	} {
+		f := NewDelimitedFrameReader(strings.NewReader(input))
+		if _, _, err := f.ReadFrame(); err != errInvalidFrame {
+			t.Errorf("%q: got err %v, want %v", input, err, errInvalidFrame)
+		}
+	}
+}
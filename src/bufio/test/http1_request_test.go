@@ -0,0 +1,181 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var errMalformedRequest = errors.New("malformed HTTP/1.1 request")
+
+type HTTPRequest struct {
+	Method  string
+	Path    string
+	Proto   string
+	Headers map[string]string // 键是规范化的形式，如 Content-Length；重复的头部用 ", " 连接
+	Body    io.Reader
+}
+
+// ParseHTTP1Request 从 conn 中解析一个 HTTP/1.1 请求。
+// 内部的 bufio.Reader 可能预读了 body 之后的数据，返回后这部分数据就丢失了，
+// 所以一个连接上只能调用一次；同一个连接上有多个请求（keep-alive、pipelining）时使用 ReadHTTP1Request。
+func ParseHTTP1Request(conn io.ReadWriter) (*HTTPRequest, error) {
+	return ReadHTTP1Request(bufio.NewReader(conn), conn)
+}
+
+// ReadHTTP1Request 从 br 中解析一个 HTTP/1.1 请求，同一个连接上的所有请求都应该使用同一个 br。
+// 请求行和头部通过 br 逐行读取；br 可能已经预读了一部分 body，
+// 所以 body 要继续从 br 读取，用 io.LimitedReader 按 Content-Length 截断，
+// 不会读到下一个请求的数据。读取下一个请求之前必须先把 body 读完。
+// 请求带有 "Expect: 100-continue" 时，先向 w 写入 100 Continue，客户端收到后才会发送 body。
+func ReadHTTP1Request(br *bufio.Reader, w io.Writer) (*HTTPRequest, error) {
+	line, err := readHTTPLine(br)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(line, " ")
+	if len(parts) != 3 || !strings.HasPrefix(parts[2], "HTTP/") {
+		return nil, errMalformedRequest
+	}
+	req := &HTTPRequest{Method: parts[0], Path: parts[1], Proto: parts[2], Headers: make(map[string]string)}
+
+	for {
+		line, err := readHTTPLine(br)
+		if err != nil {
+			return nil, err
+		}
+		if line == "" { // 空行表示头部结束
+			break
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, errMalformedRequest
+		}
+		key = textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if prev, ok := req.Headers[key]; ok {
+			value = prev + ", " + value
+		}
+		req.Headers[key] = value
+	}
+
+	var length int64
+	if cl, ok := req.Headers["Content-Length"]; ok {
+		length, err = strconv.ParseInt(cl, 10, 64)
+		if err != nil || length < 0 {
+			return nil, errMalformedRequest
+		}
+	}
+	if length > 0 && strings.EqualFold(req.Headers["Expect"], "100-continue") {
+		if _, err := io.WriteString(w, "HTTP/1.1 100 Continue\r\n\r\n"); err != nil {
+			return nil, err
+		}
+	}
+	req.Body = &io.LimitedReader{R: br, N: length}
+	return req, nil
+}
+
+// readHTTPLine 读取一行并去掉结尾的 "\r\n"
+func readHTTPLine(br *bufio.Reader) (string, error) {
+	line, err := br.ReadString('\n')
+	if err != nil {
+		if err == io.EOF && line != "" {
+			err = io.ErrUnexpectedEOF
+		}
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
+
+// fakeConn 从 in 读取请求，写出的响应保存在 out 中
+type fakeConn struct {
+	in  io.Reader
+	out bytes.Buffer
+}
+
+func (c *fakeConn) Read(p []byte) (int, error)  { return c.in.Read(p) }
+func (c *fakeConn) Write(p []byte) (int, error) { return c.out.Write(p) }
+
+func TestParseHTTP1RequestGET(t *testing.T) {
+	raw := "GET /index.html?q=go HTTP/1.1\r\n" +
+		"Host: example.com\r\n" +
+		"user-agent: test\r\n" +
+		"Accept: text/html\r\n" +
+		"Accept: application/json\r\n" +
+		"\r\n"
+	req, err := ParseHTTP1Request(&fakeConn{in: bytes.NewBufferString(raw)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Method != "GET" || req.Path != "/index.html?q=go" || req.Proto != "HTTP/1.1" {
+		t.Errorf("got request line %q %q %q", req.Method, req.Path, req.Proto)
+	}
+	want := map[string]string{
+		"Host":       "example.com",
+		"User-Agent": "test",
+		"Accept":     "text/html, application/json",
+	}
+	for k, v := range want {
+		if req.Headers[k] != v {
+			t.Errorf("header %s: got %q, want %q", k, req.Headers[k], v)
+		}
+	}
+	if body, _ := io.ReadAll(req.Body); len(body) != 0 {
+		t.Errorf("got body %q, want empty", body)
+	}
+}
+
+func TestParseHTTP1RequestPOST(t *testing.T) {
+	body := `{"proverb":"Clear is better than clever"}`
+	raw := "POST /proverbs HTTP/1.1\r\n" +
+		"Host: example.com\r\n" +
+		"Content-Type: application/json\r\n" +
+		"Content-Length: " + strconv.Itoa(len(body)) + "\r\n" +
+		"Expect: 100-continue\r\n" +
+		"\r\n" +
+		body +
+		"GET /next HTTP/1.1\r\n\r\n" // 同一个连接上的下一个请求
+
+	conn := &fakeConn{in: bytes.NewBufferString(raw)}
+	br := bufio.NewReader(conn)
+	req, err := ReadHTTP1Request(br, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(req.Body)
+	if err != nil || string(got) != body {
+		t.Errorf("got body (%q, %v), want %q", got, err, body)
+	}
+	if conn.out.String() != "HTTP/1.1 100 Continue\r\n\r\n" {
+		t.Errorf("got response %q, want 100 Continue", conn.out.String())
+	}
+
+	// 下一个请求已经被 br 预读，用同一个 br 继续解析
+	next, err := ReadHTTP1Request(br, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Method != "GET" || next.Path != "/next" {
+		t.Errorf("got next request %q %q, want GET /next", next.Method, next.Path)
+	}
+}
+
+func TestParseHTTP1RequestMalformed(t *testing.T) {
+	for _, raw := range []string{
+		"GET /\r\n\r\n",
+		"GET / HTTP/1.1\r\nHost example.com\r\n\r\n",
+		"POST / HTTP/1.1\r\nContent-Length: -1\r\n\r\n",
+	} {
+		if _, err := ParseHTTP1Request(&fakeConn{in: strings.NewReader(raw)}); err != errMalformedRequest {
+			t.Errorf("%q: got err %v, want %v", raw, err, errMalformedRequest)
+		}
+	}
+	if _, err := ParseHTTP1Request(&fakeConn{in: strings.NewReader("GET / HTTP/1.1\r\nHost: a")}); err != io.ErrUnexpectedEOF {
+		t.Errorf("got err %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+var ErrMalformedConfig = errors.New("malformed config line")
+
+// ParseConfig 解析 key=value 格式的配置文件。
+// 以 # 开头的行是注释，空行和只有空白字符的行被忽略；
+// 只按第一个 = 分割，值中可以包含 =；键和值两边的空白字符会被去掉，后出现的键覆盖先出现的。
+// 既不是注释也不包含 = 的行返回 ErrMalformedConfig，错误中带有行号。
+func ParseConfig(r io.Reader) (map[string]string, error) {
+	config := make(map[string]string)
+	s := bufio.NewScanner(r)
+	s.Split(bufio.ScanLines)
+	for lineno := 1; s.Scan(); lineno++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: %q: %w", lineno, line, ErrMalformedConfig)
+		}
+		config[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func TestParseConfig(t *testing.T) {
+	input := `# server
+host = example.com
+port=8080
+
+  # indented comment
+timeout = 30s
+
+dsn = user=admin password=secret dbname=app
+query = a==b
+empty =
+  spaced   =   value with spaces
+# port=9090
+
+log.level=debug
+	
+log.file = /var/log/app.log
+url = https://example.com/?q=1&r=2
+host = override.example.com
+
+# end
+`
+	if n := strings.Count(input, "\n"); n != 20 {
+		t.Fatalf("config has %d lines, want 20", n)
+	}
+
+	got, err := ParseConfig(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"host":      "override.example.com",
+		"port":      "8080",
+		"timeout":   "30s",
+		"dsn":       "user=admin password=secret dbname=app",
+		"query":     "a==b",
+		"empty":     "",
+		"spaced":    "value with spaces",
+		"log.level": "debug",
+		"log.file":  "/var/log/app.log",
+		"url":       "https://example.com/?q=1&r=2",
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d keys %v, want %d", len(got), got, len(want))
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: got %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestParseConfigMalformed(t *testing.T) {
+	_, err := ParseConfig(strings.NewReader("host = example.com\n\nport 8080\n"))
+	if !errors.Is(err, ErrMalformedConfig) {
+		t.Fatalf("got err %v, want %v", err, ErrMalformedConfig)
+	}
+	if !strings.HasPrefix(err.Error(), "line 3:") {
+		t.Errorf("got err %q, want line number 3", err)
+	}
+}
@@ -0,0 +1,55 @@
+package test
+
+import (
+	"bufio"
+	"io"
+	"testing"
+)
+
+// NewBufferedReadWriter 用同一个 io.ReadWriter 一步创建 bufio.ReadWriter，
+// 省去 bufio.NewReadWriter(bufio.NewReader(rw), bufio.NewWriter(rw)) 的写法。
+// 缓存大小小于等于 0 时使用 bufio 的默认大小。
+func NewBufferedReadWriter(rw io.ReadWriter, readBuf, writeBuf int) *bufio.ReadWriter {
+	var r *bufio.Reader
+	if readBuf > 0 {
+		r = bufio.NewReaderSize(rw, readBuf)
+	} else {
+		r = bufio.NewReader(rw)
+	}
+
+	var w *bufio.Writer
+	if writeBuf > 0 {
+		w = bufio.NewWriterSize(rw, writeBuf)
+	} else {
+		w = bufio.NewWriter(rw)
+	}
+	return bufio.NewReadWriter(r, w)
+}
+
+// loopback 把写入 PipeWriter 的数据从 PipeReader 读回来
+type loopback struct {
+	*io.PipeReader
+	*io.PipeWriter
+}
+
+func TestBufferedReadWriter(t *testing.T) {
+	pr, pw := io.Pipe()
+	rw := NewBufferedReadWriter(loopback{pr, pw}, 16, 16)
+
+	// io.Pipe 没有缓存，写入会阻塞到数据被读走为止，所以写和读要放在不同的 goroutine 中
+	go func() {
+		rw.WriteString("Clear is better than clever\n")
+		rw.WriteString("Don't panic\n")
+		rw.Flush()
+	}()
+
+	for _, want := range []string{"Clear is better than clever\n", "Don't panic\n"} {
+		line, err := rw.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line != want {
+			t.Errorf("got %q, want %q", line, want)
+		}
+	}
+}
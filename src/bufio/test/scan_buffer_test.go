@@ -0,0 +1,55 @@
+package test
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+// Scanner 的缓存从 Buffer 设置的初始大小开始，token 放不下时按两倍增长，直到 max 为止。
+// SplitFunc 在缓存中找不到完整的 token 时返回 (0, nil, nil)，Scanner 就会扩大缓存再读取更多数据。
+func TestScannerBufferGrowth(t *testing.T) {
+	sizes := []int{17, 100, 65536}
+	var lines []string
+	for i, n := range sizes {
+		lines = append(lines, strings.Repeat(string(rune('a'+i)), n))
+	}
+
+	s := bufio.NewScanner(strings.NewReader(strings.Join(lines, "\n")))
+	s.Buffer(make([]byte, 16), 1<<20)
+
+	i := 0
+	for s.Scan() {
+		if i >= len(lines) {
+			t.Fatalf("got extra token of %d bytes", len(s.Bytes()))
+		}
+		if s.Text() != lines[i] {
+			t.Errorf("token %d: got %d bytes, want %d", i, len(s.Bytes()), len(lines[i]))
+		}
+		i++
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if i != len(lines) {
+		t.Errorf("got %d tokens, want %d", i, len(lines))
+	}
+}
+
+// token 超过 max 时 Scan 返回 false，Err 返回 bufio.ErrTooLong
+func TestScannerBufferTooLong(t *testing.T) {
+	input := "short\n" + strings.Repeat("x", 100) + "\nnever reached\n"
+	s := bufio.NewScanner(strings.NewReader(input))
+	s.Buffer(make([]byte, 16), 64)
+
+	var got []string
+	for s.Scan() {
+		got = append(got, s.Text())
+	}
+	if len(got) != 1 || got[0] != "short" {
+		t.Errorf("got tokens %q, want [short]", got)
+	}
+	if s.Err() != bufio.ErrTooLong {
+		t.Errorf("got err %v, want %v", s.Err(), bufio.ErrTooLong)
+	}
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var errUnterminatedQuote = errors.New("csv: unterminated quoted field")
+
+// ScanCSVRecords 是一个 bufio.SplitFunc，每次切分出一条 CSV 记录（不包含行尾的 "\n" 或 "\r\n"）。
+// 只有不在双引号字段内的换行才表示记录结束。
+// 字段内转义的双引号 "" 会让引号状态翻转两次，最终仍在字段内，所以无需特殊处理。
+func ScanCSVRecords(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	inQuote := false
+	for i, c := range data {
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+		case c == '\n' && !inQuote:
+			return i + 1, dropCR(data[:i]), nil
+		}
+	}
+
+	if !atEOF {
+		// 记录还不完整，请求更多的数据
+		return 0, nil, nil
+	}
+	if inQuote {
+		return 0, nil, errUnterminatedQuote
+	}
+	return len(data), dropCR(data), nil
+}
+
+func dropCR(data []byte) []byte {
+	if len(data) > 0 && data[len(data)-1] == '\r' {
+		return data[:len(data)-1]
+	}
+	return data
+}
+
+func TestScanCSVRecords(t *testing.T) {
+	input := "name,note\r\n" +
+		"gopher,\"line1\nline2\"\n" +
+		"\"say \"\"hi\"\"\",\"a\n\"\"b\"\"\nc\"\n" +
+		"last,record"
+	want := []string{
+		"name,note",
+		"gopher,\"line1\nline2\"",
+		"\"say \"\"hi\"\"\",\"a\n\"\"b\"\"\nc\"",
+		"last,record",
+	}
+
+	s := bufio.NewScanner(strings.NewReader(input))
+	// 使用很小的初始缓存，让多行字段跨越多次缓存加载
+	s.Buffer(make([]byte, 8), 1024)
+	s.Split(ScanCSVRecords)
+
+	var got []string
+	for s.Scan() {
+		got = append(got, s.Text())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d records %q, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("record %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestScanCSVRecordsUnterminated(t *testing.T) {
+	s := bufio.NewScanner(strings.NewReader("a,\"b\nc"))
+	s.Split(ScanCSVRecords)
+	for s.Scan() {
+	}
+	if s.Err() != errUnterminatedQuote {
+		t.Errorf("got err %v, want %v", s.Err(), errUnterminatedQuote)
+	}
+}
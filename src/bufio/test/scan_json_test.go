@@ -0,0 +1,127 @@
+package test
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var errInvalidJSONObject = errors.New("invalid json object")
+
+// ScanJSONObjects 是一个 bufio.SplitFunc，从流中依次切分出完整的 JSON 对象。
+// 对象之间可以没有分隔符，也可以用空白字符分隔。
+// 通过记录括号深度来判断对象是否结束，字符串中的括号（包括转义字符）不参与计数。
+func ScanJSONObjects(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	start := 0
+	for start < len(data) && isJSONSpace(data[start]) {
+		start++
+	}
+	if start == len(data) {
+		// 全是空白字符，直接跳过
+		return start, nil, nil
+	}
+	if data[start] != '{' {
+		return 0, nil, errInvalidJSONObject
+	}
+
+	depth := 0
+	inString, escaped := false, false
+	for i := start; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return i + 1, data[start : i+1], nil
+			}
+		}
+	}
+
+	if atEOF {
+		return 0, nil, errInvalidJSONObject
+	}
+	// 对象还不完整，丢弃前导空白后请求更多的数据
+	return start, nil, nil
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+func scanJSON(t *testing.T, s *bufio.Scanner) []string {
+	var objs []string
+	for s.Scan() {
+		objs = append(objs, s.Text())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return objs
+}
+
+func TestScanJSONObjects(t *testing.T) {
+	input := `{"a":1}{"b":{"c":[1,2,{"d":3}]}}` + "\n" + `{"e":[]}`
+	want := []string{`{"a":1}`, `{"b":{"c":[1,2,{"d":3}]}}`, `{"e":[]}`}
+
+	// 每次只读一个字节，并且使用很小的初始缓存，保证对象会跨越多次缓存加载
+	s := bufio.NewScanner(iotest.OneByteReader(strings.NewReader(input)))
+	s.Buffer(make([]byte, 4), 1024)
+	s.Split(ScanJSONObjects)
+
+	got := scanJSON(t, s)
+	if len(got) != len(want) {
+		t.Fatalf("got %d objects %q, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		fmt.Println(got[i])
+		if got[i] != want[i] {
+			t.Errorf("object %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestScanJSONObjectsBracesInString(t *testing.T) {
+	input := `{"s":"{}"}{"s":"}\"{"}{"s":"\\"}`
+	want := []string{`{"s":"{}"}`, `{"s":"}\"{"}`, `{"s":"\\"}`}
+
+	s := bufio.NewScanner(strings.NewReader(input))
+	s.Split(ScanJSONObjects)
+
+	got := scanJSON(t, s)
+	if len(got) != len(want) {
+		t.Fatalf("got %d objects %q, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("object %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestScanJSONObjectsTruncated(t *testing.T) {
+	s := bufio.NewScanner(strings.NewReader(`{"a":1}{"b":`))
+	s.Split(ScanJSONObjects)
+	for s.Scan() {
+	}
+	if s.Err() != errInvalidJSONObject {
+		t.Errorf("got err %v, want %v", s.Err(), errInvalidJSONObject)
+	}
+}
@@ -0,0 +1,70 @@
+package test
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// ScanLinesUniversal 是一个 bufio.SplitFunc，与 bufio.ScanLines 类似，
+// 但同时把 "\n"（Unix）、"\r\n"（Windows）和单独的 "\r"（classic Mac OS）当作行尾，返回的行不包含行尾。
+// bufio.ScanLines 只认 "\n"，单独的 "\r" 会留在行中间。
+// data 以 "\r" 结尾时还不能确定后面是不是 "\n"，需要请求更多的数据，除非已经到达 EOF。
+func ScanLinesUniversal(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	for i, c := range data {
+		switch c {
+		case '\n':
+			return i + 1, data[:i], nil
+		case '\r':
+			if i+1 < len(data) {
+				if data[i+1] == '\n' {
+					return i + 2, data[:i], nil
+				}
+				return i + 1, data[:i], nil
+			}
+			if atEOF {
+				return i + 1, data[:i], nil
+			}
+			return 0, nil, nil
+		}
+	}
+	if !atEOF {
+		return 0, nil, nil
+	}
+	// 最后一行没有行尾
+	return len(data), data, nil
+}
+
+func TestScanLinesUniversal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"line1\r\nline2\rline3\nline4", []string{"line1", "line2", "line3", "line4"}},
+		{"a\n\nb\r\rc\r\n\r\n", []string{"a", "", "b", "", "c", ""}}, // 空行
+		{"a\r", []string{"a"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		// OneByteReader 让 "\r\n" 被拆到两次 Read 中
+		s := bufio.NewScanner(iotest.OneByteReader(strings.NewReader(tt.in)))
+		s.Split(ScanLinesUniversal)
+		var got []string
+		for s.Scan() {
+			if strings.ContainsAny(s.Text(), "\r\n") {
+				t.Errorf("%q: token %q contains line ending", tt.in, s.Text())
+			}
+			got = append(got, s.Text())
+		}
+		if err := s.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+			t.Errorf("%q: got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
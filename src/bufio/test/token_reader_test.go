@@ -0,0 +1,86 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenReader 从流中读取以空白字符分隔的 token，分隔规则与 strings.Fields 相同。
+// TokenReader 本身也是 io.Reader，Read 从当前位置（上一个 token 之后）读取原始数据，
+// 可以在读取若干 token 之后把剩下的数据交给其他的解析器处理。
+type TokenReader struct {
+	r *bufio.Reader
+}
+
+func NewTokenReader(r io.Reader) *TokenReader {
+	return &TokenReader{r: bufio.NewReader(r)}
+}
+
+// ReadToken 跳过前导的空白字符，返回下一个 token，没有更多 token 时返回 io.EOF。
+// 通过 Peek 逐个解码 rune，分隔符不会被消费，非法的 UTF-8 字节原样保留在 token 中。
+func (t *TokenReader) ReadToken() ([]byte, error) {
+	var token []byte
+	for {
+		buf, err := t.r.Peek(utf8.UTFMax)
+		if len(buf) == 0 {
+			if err == io.EOF && len(token) > 0 {
+				return token, nil
+			}
+			return nil, err
+		}
+		c, size := utf8.DecodeRune(buf)
+		if unicode.IsSpace(c) {
+			if len(token) > 0 {
+				return token, nil
+			}
+		} else {
+			token = append(token, buf[:size]...)
+		}
+		t.r.Discard(size)
+	}
+}
+
+func (t *TokenReader) Read(p []byte) (int, error) {
+	return t.r.Read(p)
+}
+
+func TestTokenReader(t *testing.T) {
+	input := "  Clear is\tbetter\n\nthan  clever\r\n\t你好，  世界 "
+	r := NewTokenReader(strings.NewReader(input))
+
+	var got []string
+	for {
+		tok, err := r.ReadToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, string(tok))
+	}
+
+	want := strings.Fields(input)
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestTokenReaderRead(t *testing.T) {
+	r := NewTokenReader(strings.NewReader("GET /index.html\nrest of body"))
+	method, _ := r.ReadToken()
+	path, _ := r.ReadToken()
+	rest, _ := io.ReadAll(r)
+
+	if string(method) != "GET" || string(path) != "/index.html" {
+		t.Errorf("got tokens %q %q", method, path)
+	}
+	if !bytes.Equal(rest, []byte("\nrest of body")) {
+		t.Errorf("got rest %q", rest)
+	}
+}
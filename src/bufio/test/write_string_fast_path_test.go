@@ -0,0 +1,69 @@
+package test
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+// spyStringWriter 记录 Write 和 WriteString 各被调用了几次
+type spyStringWriter struct {
+	strings.Builder
+	writes, writeStrings int
+}
+
+func (w *spyStringWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Builder.Write(p)
+}
+
+func (w *spyStringWriter) WriteString(s string) (int, error) {
+	w.writeStrings++
+	return w.Builder.WriteString(s)
+}
+
+// bufio.Writer.WriteString 只在缓存为空、且字符串比剩余空间大时，
+// 才把字符串直接交给底层的 io.StringWriter，省去一次复制到缓存和一次 []byte 转换。
+// 其他情况下字符串先复制到缓存，Flush 时仍然调用底层的 Write。
+func TestBufferedWriteString_FastPath(t *testing.T) {
+	w := new(spyStringWriter)
+	bw := bufio.NewWriterSize(w, 16)
+
+	s := strings.Repeat("Go", 16) // 32 字节，超过缓存大小
+	if _, err := bw.WriteString(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if w.writeStrings != 1 || w.writes != 0 {
+		t.Errorf("got %d WriteString and %d Write calls, want 1 and 0", w.writeStrings, w.writes)
+	}
+	if w.String() != s {
+		t.Errorf("got %q, want %q", w.String(), s)
+	}
+}
+
+func TestBufferedWriteString_SlowPath(t *testing.T) {
+	w := new(spyStringWriter)
+	bw := bufio.NewWriterSize(w, 16)
+
+	// 字符串能放进缓存，Flush 时通过 Write 写出
+	bw.WriteString("Go")
+	bw.Flush()
+	if w.writeStrings != 0 || w.writes != 1 {
+		t.Errorf("got %d WriteString and %d Write calls, want 0 and 1", w.writeStrings, w.writes)
+	}
+
+	// 缓存不为空时，先填满缓存并 Flush，剩下的部分再走快速路径
+	w.writes = 0
+	bw.WriteString("Go")
+	bw.WriteString(strings.Repeat("x", 40))
+	bw.Flush()
+	if w.writeStrings != 1 || w.writes != 1 {
+		t.Errorf("got %d WriteString and %d Write calls, want 1 and 1", w.writeStrings, w.writes)
+	}
+	if want := "GoGo" + strings.Repeat("x", 40); w.String() != want {
+		t.Errorf("got %q, want %q", w.String(), want)
+	}
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// bytes.Buffer：可读可写，读过的数据不会立刻释放，空间不够时会先把未读数据移到开头再考虑扩容。
+// strings.Builder：只能写，String() 直接复用底层数组而不复制，所以写入之后不能修改。
+// bytes.Reader：只读，不会分配内存，支持 Seek 和 ReadAt。
+
+const compareSize = 1 << 20
+
+var chunk = []byte(strings.Repeat("x", 64))
+
+func BenchmarkBufferWrite(b *testing.B) {
+	b.SetBytes(compareSize)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var buf bytes.Buffer
+		for buf.Len() < compareSize {
+			buf.Write(chunk)
+		}
+		_ = buf.String()
+	}
+}
+
+func BenchmarkBuilderWrite(b *testing.B) {
+	b.SetBytes(compareSize)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var sb strings.Builder
+		for sb.Len() < compareSize {
+			sb.Write(chunk)
+		}
+		_ = sb.String()
+	}
+}
+
+func BenchmarkReaderRead(b *testing.B) {
+	data := bytes.Repeat(chunk, compareSize/len(chunk))
+	p := make([]byte, len(chunk))
+	r := bytes.NewReader(data)
+
+	b.SetBytes(compareSize)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.Reset(data)
+		for {
+			if _, err := r.Read(p); err == io.EOF {
+				break
+			}
+		}
+	}
+}
+
+// 在长度达到 2 的幂时打印容量，观察扩容的过程。
+// bytes.Reader 只包装已有的切片，不会扩容，所以只比较 Buffer 和 Builder。
+func TestGrowthComparison(t *testing.T) {
+	var buf bytes.Buffer
+	var sb strings.Builder
+
+	fmt.Printf("%8s %12s %12s\n", "len", "Buffer cap", "Builder cap")
+	next := 1
+	for n := 1; n <= 1<<16; n++ {
+		buf.WriteByte('x')
+		sb.WriteByte('x')
+		if n == next {
+			fmt.Printf("%8d %12d %12d\n", n, buf.Cap(), sb.Cap())
+			if buf.Cap() < n || sb.Cap() < n {
+				t.Errorf("len %d: cap smaller than len", n)
+			}
+			next *= 2
+		}
+	}
+
+	r := bytes.NewReader(buf.Bytes())
+	if r.Size() != int64(buf.Len()) {
+		t.Errorf("Reader size %d, want %d", r.Size(), buf.Len())
+	}
+}
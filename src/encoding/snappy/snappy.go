@@ -0,0 +1,409 @@
+// Package snappy implements streaming Snappy compression using the
+// Snappy framing format described at
+// https://github.com/google/snappy/blob/main/framing_format.txt.
+//
+// The stream is a sequence of chunks. Each chunk starts with a one byte
+// type and a three byte little-endian length. Data chunks carry at most
+// 64 KiB of uncompressed data and a masked CRC-32C checksum of it.
+//
+// snappy包实现了Snappy分帧格式的流式压缩与解压，不依赖任何外部包。
+// 每个数据块最多包含64KiB未压缩数据，并带有未压缩数据的CRC-32C校验和。
+package snappy
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+var (
+	// ErrCorrupt reports that the input is invalid.
+	ErrCorrupt = errors.New("snappy: corrupt input")
+	// ErrUnsupported reports that the input uses a reserved unskippable chunk type.
+	ErrUnsupported = errors.New("snappy: unsupported input")
+
+	errClosed = errors.New("snappy: write on closed Writer")
+)
+
+const (
+	chunkTypeCompressedData   = 0x00
+	chunkTypeUncompressedData = 0x01
+	chunkTypePadding          = 0xfe
+	chunkTypeStreamIdentifier = 0xff
+
+	magicBody  = "sNaPpY"
+	magicChunk = "\xff\x06\x00\x00" + magicBody
+
+	// maxBlockSize is the maximum size of the uncompressed data in a chunk.
+	maxBlockSize = 65536
+
+	checksumSize    = 4
+	chunkHeaderSize = 4
+
+	// maxEncodedLen is the worst case size of an encoded block.
+	maxEncodedLen = 32 + maxBlockSize + maxBlockSize/6
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// crc returns the masked CRC-32C checksum of b, as required by the framing format.
+func crc(b []byte) uint32 {
+	c := crc32.Update(0, crcTable, b)
+	return (c>>15 | c<<17) + 0xa282ead8
+}
+
+// Block format.
+//
+// A block starts with the uncompressed length as a uvarint, followed by
+// a sequence of elements. The low two bits of each element's tag byte
+// give its type: a literal run, or a copy of earlier output with a one,
+// two or four byte offset.
+
+const (
+	tagLiteral = 0x00
+	tagCopy1   = 0x01
+	tagCopy2   = 0x02
+	tagCopy4   = 0x03
+
+	tableBits = 14
+	tableSize = 1 << tableBits
+)
+
+func load32(b []byte, i int) uint32 {
+	return binary.LittleEndian.Uint32(b[i:])
+}
+
+func hash(u uint32) uint32 {
+	return (u * 0x1e35a7bd) >> (32 - tableBits)
+}
+
+// encodeBlock appends the Snappy block encoding of src to dst.
+// src must be no longer than maxBlockSize, so every match offset fits in
+// a two byte copy.
+func encodeBlock(dst, src []byte) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(src)))
+
+	// table maps the hash of four bytes to their position plus one.
+	var table [tableSize]int32
+	lit := 0
+	for i := 0; i+4 <= len(src); {
+		u := load32(src, i)
+		h := hash(u)
+		cand := int(table[h]) - 1
+		table[h] = int32(i + 1)
+		if cand < 0 || load32(src, cand) != u {
+			i++
+			continue
+		}
+
+		dst = emitLiteral(dst, src[lit:i])
+		j, k := i+4, cand+4
+		for j < len(src) && src[j] == src[k] {
+			j, k = j+1, k+1
+		}
+		dst = emitCopy(dst, i-cand, j-i)
+		i, lit = j, j
+	}
+	return emitLiteral(dst, src[lit:])
+}
+
+func emitLiteral(dst, lit []byte) []byte {
+	if len(lit) == 0 {
+		return dst
+	}
+	switch n := len(lit) - 1; {
+	case n < 60:
+		dst = append(dst, byte(n)<<2|tagLiteral)
+	case n < 1<<8:
+		dst = append(dst, 60<<2|tagLiteral, byte(n))
+	default:
+		dst = append(dst, 61<<2|tagLiteral, byte(n), byte(n>>8))
+	}
+	return append(dst, lit...)
+}
+
+func emitCopy(dst []byte, offset, length int) []byte {
+	for length > 0 {
+		n := length
+		if n > 64 {
+			n = 64
+		}
+		dst = append(dst, byte(n-1)<<2|tagCopy2, byte(offset), byte(offset>>8))
+		length -= n
+	}
+	return dst
+}
+
+// decodeBlock decodes the Snappy block src into dst, reusing dst's storage
+// when it is large enough.
+func decodeBlock(dst, src []byte) ([]byte, error) {
+	dLen, n := binary.Uvarint(src)
+	if n <= 0 || dLen > maxBlockSize {
+		return nil, ErrCorrupt
+	}
+	src = src[n:]
+	if cap(dst) < int(dLen) {
+		dst = make([]byte, 0, dLen)
+	}
+	dst = dst[:0]
+
+	for len(src) > 0 {
+		tag := src[0]
+		var offset, length int
+		switch tag & 0x03 {
+		case tagLiteral:
+			x := int(tag >> 2)
+			src = src[1:]
+			switch {
+			case x < 60:
+			case x == 60 && len(src) >= 1:
+				x, src = int(src[0]), src[1:]
+			case x == 61 && len(src) >= 2:
+				x, src = int(src[0])|int(src[1])<<8, src[2:]
+			case x == 62 && len(src) >= 3:
+				x, src = int(src[0])|int(src[1])<<8|int(src[2])<<16, src[3:]
+			case x == 63 && len(src) >= 4:
+				x, src = int(binary.LittleEndian.Uint32(src)), src[4:]
+			default:
+				return nil, ErrCorrupt
+			}
+			length = x + 1
+			if length <= 0 || length > len(src) || len(dst)+length > int(dLen) {
+				return nil, ErrCorrupt
+			}
+			dst = append(dst, src[:length]...)
+			src = src[length:]
+			continue
+		case tagCopy1:
+			if len(src) < 2 {
+				return nil, ErrCorrupt
+			}
+			length = 4 + int(tag>>2)&0x07
+			offset = int(tag&0xe0)<<3 | int(src[1])
+			src = src[2:]
+		case tagCopy2:
+			if len(src) < 3 {
+				return nil, ErrCorrupt
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint16(src[1:]))
+			src = src[3:]
+		case tagCopy4:
+			if len(src) < 5 {
+				return nil, ErrCorrupt
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint32(src[1:]))
+			src = src[5:]
+		}
+
+		if offset <= 0 || offset > len(dst) || len(dst)+length > int(dLen) {
+			return nil, ErrCorrupt
+		}
+		// The copy may overlap the bytes it produces, so copy one byte at a time.
+		for i := len(dst) - offset; length > 0; i, length = i+1, length-1 {
+			dst = append(dst, dst[i])
+		}
+	}
+
+	if len(dst) != int(dLen) {
+		return nil, ErrCorrupt
+	}
+	return dst, nil
+}
+
+// Writer is an io.WriteCloser that compresses data into the Snappy framing format.
+type Writer struct {
+	w      io.Writer
+	err    error
+	buf    []byte // uncompressed data waiting to be written as a chunk
+	out    []byte // scratch space for the encoded chunk
+	wrote  bool   // whether the stream identifier has been written
+	closed bool
+}
+
+// NewSnappyWriter returns a new Writer that compresses to w.
+// Data is buffered into chunks of up to 64 KiB; it is the caller's
+// responsibility to call Close when done, which flushes the last chunk.
+func NewSnappyWriter(w io.Writer) io.WriteCloser {
+	return &Writer{w: w, buf: make([]byte, 0, maxBlockSize)}
+}
+
+// Write compresses p and writes it to the underlying writer one chunk at a time.
+func (w *Writer) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		if w.err != nil {
+			return n, w.err
+		}
+		m := copy(w.buf[len(w.buf):maxBlockSize], p)
+		w.buf = w.buf[:len(w.buf)+m]
+		n += m
+		p = p[m:]
+		if len(w.buf) == maxBlockSize {
+			w.writeChunk()
+		}
+	}
+	return n, w.err
+}
+
+// Flush writes any buffered data to the underlying writer as a chunk.
+func (w *Writer) Flush() error {
+	if w.err == nil && (len(w.buf) > 0 || !w.wrote) {
+		w.writeChunk()
+	}
+	return w.err
+}
+
+// Close flushes buffered data. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	err := w.Flush()
+	if w.err == nil {
+		w.err = errClosed
+	}
+	return err
+}
+
+func (w *Writer) writeChunk() {
+	out := w.out[:0]
+	if !w.wrote {
+		out = append(out, magicChunk...)
+		w.wrote = true
+	}
+
+	if len(w.buf) > 0 {
+		// Leave room for the chunk header and checksum, filled in below.
+		start := len(out)
+		out = append(out, make([]byte, chunkHeaderSize+checksumSize)...)
+		chunkType := byte(chunkTypeCompressedData)
+		out = encodeBlock(out, w.buf)
+		// Store the data uncompressed if compression does not save at least 12.5%.
+		if n := len(out) - start - chunkHeaderSize - checksumSize; n >= len(w.buf)-len(w.buf)/8 {
+			chunkType = chunkTypeUncompressedData
+			out = append(out[:start+chunkHeaderSize+checksumSize], w.buf...)
+		}
+		length := len(out) - start - chunkHeaderSize
+		out[start] = chunkType
+		out[start+1] = byte(length)
+		out[start+2] = byte(length >> 8)
+		out[start+3] = byte(length >> 16)
+		binary.LittleEndian.PutUint32(out[start+chunkHeaderSize:], crc(w.buf))
+	}
+
+	if _, err := w.w.Write(out); err != nil {
+		w.err = err
+	}
+	w.out = out
+	w.buf = w.buf[:0]
+}
+
+// Reader is an io.Reader that decompresses data in the Snappy framing format.
+type Reader struct {
+	r       io.Reader
+	err     error
+	header  [chunkHeaderSize]byte
+	chunk   []byte // the current chunk body
+	decoded []byte // decoded data of the current chunk
+	off     int    // read offset in decoded
+	magic   bool   // whether the stream identifier has been read
+}
+
+// NewSnappyReader returns a new Reader that decompresses from r.
+func NewSnappyReader(r io.Reader) io.Reader {
+	return &Reader{r: r}
+}
+
+// Read decompresses data into p, reading more chunks from the underlying
+// reader as needed.
+//
+// A stream that ends on a chunk boundary returns io.EOF. A stream that ends
+// in the middle of a chunk header or body returns io.ErrUnexpectedEOF.
+// Chunks that are complete but invalid return ErrCorrupt.
+func (r *Reader) Read(p []byte) (int, error) {
+	for r.off == len(r.decoded) {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.err = r.nextChunk()
+	}
+	n := copy(p, r.decoded[r.off:])
+	r.off += n
+	return n, nil
+}
+
+// nextChunk reads the next chunk, leaving any data it carries in r.decoded.
+func (r *Reader) nextChunk() error {
+	r.decoded, r.off = r.decoded[:0], 0
+
+	if _, err := io.ReadFull(r.r, r.header[:]); err != nil {
+		return err
+	}
+	chunkType := r.header[0]
+	length := int(r.header[1]) | int(r.header[2])<<8 | int(r.header[3])<<16
+
+	if !r.magic && chunkType != chunkTypeStreamIdentifier {
+		return ErrCorrupt
+	}
+	switch {
+	case chunkType >= 0x02 && chunkType <= 0x7f:
+		return ErrUnsupported
+	case chunkType >= 0x80 && chunkType <= chunkTypePadding:
+		// Padding and reserved skippable chunks are ignored.
+		if _, err := io.CopyN(io.Discard, r.r, int64(length)); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		return nil
+	case length > checksumSize+maxEncodedLen:
+		return ErrCorrupt
+	}
+
+	if cap(r.chunk) < length {
+		r.chunk = make([]byte, length)
+	}
+	r.chunk = r.chunk[:length]
+	if _, err := io.ReadFull(r.r, r.chunk); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	switch chunkType {
+	case chunkTypeStreamIdentifier:
+		if string(r.chunk) != magicBody {
+			return ErrCorrupt
+		}
+		r.magic = true
+	case chunkTypeCompressedData, chunkTypeUncompressedData:
+		if length < checksumSize {
+			return ErrCorrupt
+		}
+		sum := binary.LittleEndian.Uint32(r.chunk)
+		data := r.chunk[checksumSize:]
+		if chunkType == chunkTypeCompressedData {
+			d, err := decodeBlock(r.decoded, data)
+			if err != nil {
+				return err
+			}
+			r.decoded = d
+		} else {
+			if len(data) > maxBlockSize {
+				return ErrCorrupt
+			}
+			r.decoded = append(r.decoded, data...)
+		}
+		if crc(r.decoded) != sum {
+			r.decoded = r.decoded[:0]
+			return ErrCorrupt
+		}
+	}
+	return nil
+}
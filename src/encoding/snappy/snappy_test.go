@@ -0,0 +1,123 @@
+package snappy
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+)
+
+// testData returns n bytes mixing compressible text with random noise.
+func testData(n int) []byte {
+	rnd := rand.New(rand.NewSource(1))
+	text := []byte("Clear is better than clever. Don't panic. Errors are values. ")
+	var b bytes.Buffer
+	for b.Len() < n {
+		if rnd.Intn(4) == 0 {
+			noise := make([]byte, rnd.Intn(256))
+			rnd.Read(noise)
+			b.Write(noise)
+		} else {
+			b.Write(text[:rnd.Intn(len(text))])
+		}
+	}
+	return b.Bytes()[:n]
+}
+
+func compress(t *testing.T, src []byte) []byte {
+	var buf bytes.Buffer
+	w := NewSnappyWriter(&buf)
+	if _, err := w.Write(src); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	src := testData(1 << 20)
+	compressed := compress(t, src)
+	if len(compressed) >= len(src) {
+		t.Errorf("compressed size %d >= original size %d", len(compressed), len(src))
+	}
+
+	got, err := io.ReadAll(NewSnappyReader(iotest.HalfReader(bytes.NewReader(compressed))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, src) {
+		t.Fatal("decompressed data differs from original")
+	}
+}
+
+func TestRoundTripIncompressible(t *testing.T) {
+	src := make([]byte, 100000)
+	rand.New(rand.NewSource(2)).Read(src)
+	compressed := compress(t, src)
+	if compressed[len(magicChunk)] != chunkTypeUncompressedData {
+		t.Errorf("got chunk type %#x, want %#x", compressed[len(magicChunk)], chunkTypeUncompressedData)
+	}
+
+	got, err := io.ReadAll(NewSnappyReader(bytes.NewReader(compressed)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, src) {
+		t.Fatal("decompressed data differs from original")
+	}
+}
+
+func TestEmptyStream(t *testing.T) {
+	compressed := compress(t, nil)
+	if string(compressed) != magicChunk {
+		t.Errorf("got %q, want %q", compressed, magicChunk)
+	}
+	got, err := io.ReadAll(NewSnappyReader(bytes.NewReader(compressed)))
+	if err != nil || len(got) != 0 {
+		t.Errorf("got (%q, %v), want empty", got, err)
+	}
+}
+
+func TestSkippableChunks(t *testing.T) {
+	compressed := compress(t, []byte("Cgo is not Go"))
+	// A padding chunk between the stream identifier and the data is ignored.
+	stream := append([]byte(magicChunk), chunkTypePadding, 3, 0, 0, 0, 0, 0)
+	stream = append(stream, compressed[len(magicChunk):]...)
+
+	got, err := io.ReadAll(NewSnappyReader(bytes.NewReader(stream)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Cgo is not Go" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestCorrupt(t *testing.T) {
+	compressed := compress(t, testData(1000))
+
+	tests := []struct {
+		name   string
+		stream []byte
+		err    error
+	}{
+		{"missing identifier", compressed[len(magicChunk):], ErrCorrupt},
+		{"bad checksum", func() []byte {
+			b := bytes.Clone(compressed)
+			b[len(magicChunk)+chunkHeaderSize] ^= 0xff
+			return b
+		}(), ErrCorrupt},
+		{"truncated", compressed[:len(compressed)-1], io.ErrUnexpectedEOF},
+		{"truncated header", compressed[:len(magicChunk)+2], io.ErrUnexpectedEOF},
+		{"unskippable", append([]byte(magicChunk), 0x02, 0, 0, 0), ErrUnsupported},
+	}
+	for _, tt := range tests {
+		_, err := io.ReadAll(NewSnappyReader(bytes.NewReader(tt.stream)))
+		if err != tt.err {
+			t.Errorf("%s: got err %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
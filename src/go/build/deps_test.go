@@ -225,7 +225,7 @@ var depsRules = `
 
 	# compression
 	FMT, encoding/binary, hash/adler32, hash/crc32
-	< compress/bzip2, compress/flate, compress/lzw
+	< compress/bzip2, compress/flate, compress/lzw, encoding/snappy
 	< archive/zip, compress/gzip, compress/zlib;
 
 	# templates
@@ -0,0 +1,155 @@
+package bench
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// 基准测试都通过 b.SetBytes 报告吞吐量（MB/s）。
+// 修改后可以用下面的命令和 bench_baseline.txt 对比（也可以用 benchstat）：
+//
+//	go test -run=NONE -bench=. -count=3 -benchtime=300ms > new.txt
+//	benchcmp bench_baseline.txt new.txt
+
+var sizes = []int{1 << 10, 32 << 10, 1 << 20}
+
+func sizeName(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%dMiB", n>>20)
+	case n >= 1<<10:
+		return fmt.Sprintf("%dKiB", n>>10)
+	}
+	return fmt.Sprintf("%dB", n)
+}
+
+// onlyReader 和 onlyWriter 隐藏了 WriterTo/ReaderFrom 等可选接口，
+// 让 io.Copy 走通用的缓存拷贝路径
+type onlyReader struct{ io.Reader }
+
+type onlyWriter struct{ io.Writer }
+
+func BenchmarkCopy(b *testing.B) {
+	for _, n := range sizes {
+		data := make([]byte, n)
+		b.Run(sizeName(n), func(b *testing.B) {
+			b.SetBytes(int64(n))
+			r := bytes.NewReader(data)
+			for i := 0; i < b.N; i++ {
+				r.Reset(data)
+				io.Copy(onlyWriter{io.Discard}, onlyReader{r})
+			}
+		})
+	}
+}
+
+// bytes.Reader 实现了 io.WriterTo，io.Copy 会直接调用 WriteTo 而不分配缓存
+func BenchmarkCopyWriterTo(b *testing.B) {
+	for _, n := range sizes {
+		data := make([]byte, n)
+		b.Run(sizeName(n), func(b *testing.B) {
+			b.SetBytes(int64(n))
+			r := bytes.NewReader(data)
+			w := bytes.NewBuffer(make([]byte, 0, n))
+			for i := 0; i < b.N; i++ {
+				r.Reset(data)
+				w.Reset()
+				io.Copy(onlyWriter{w}, r)
+			}
+		})
+	}
+}
+
+func BenchmarkCopyN(b *testing.B) {
+	for _, n := range sizes {
+		data := make([]byte, n)
+		b.Run(sizeName(n), func(b *testing.B) {
+			b.SetBytes(int64(n))
+			r := bytes.NewReader(data)
+			for i := 0; i < b.N; i++ {
+				r.Reset(data)
+				io.CopyN(io.Discard, r, int64(n))
+			}
+		})
+	}
+}
+
+func BenchmarkReadAll(b *testing.B) {
+	for _, n := range sizes {
+		data := make([]byte, n)
+		b.Run(sizeName(n), func(b *testing.B) {
+			b.SetBytes(int64(n))
+			r := bytes.NewReader(data)
+			for i := 0; i < b.N; i++ {
+				r.Reset(data)
+				io.ReadAll(r)
+			}
+		})
+	}
+}
+
+func BenchmarkReadFull(b *testing.B) {
+	for _, n := range sizes {
+		data := make([]byte, n)
+		buf := make([]byte, n)
+		b.Run(sizeName(n), func(b *testing.B) {
+			b.SetBytes(int64(n))
+			r := bytes.NewReader(data)
+			for i := 0; i < b.N; i++ {
+				r.Reset(data)
+				io.ReadFull(r, buf)
+			}
+		})
+	}
+}
+
+func BenchmarkTeeReader(b *testing.B) {
+	for _, n := range sizes {
+		data := make([]byte, n)
+		b.Run(sizeName(n), func(b *testing.B) {
+			b.SetBytes(int64(n))
+			r := bytes.NewReader(data)
+			var w bytes.Buffer
+			for i := 0; i < b.N; i++ {
+				r.Reset(data)
+				w.Reset()
+				io.Copy(io.Discard, io.TeeReader(r, &w))
+			}
+		})
+	}
+}
+
+func BenchmarkLimitReader(b *testing.B) {
+	for _, n := range sizes {
+		data := make([]byte, 2*n)
+		b.Run(sizeName(n), func(b *testing.B) {
+			b.SetBytes(int64(n))
+			r := bytes.NewReader(data)
+			for i := 0; i < b.N; i++ {
+				r.Reset(data)
+				io.Copy(io.Discard, io.LimitReader(r, int64(n)))
+			}
+		})
+	}
+}
+
+func BenchmarkSectionReaderRead(b *testing.B) {
+	for _, n := range sizes {
+		data := make([]byte, 2*n)
+		buf := make([]byte, 32<<10)
+		b.Run(sizeName(n), func(b *testing.B) {
+			b.SetBytes(int64(n))
+			ra := bytes.NewReader(data)
+			for i := 0; i < b.N; i++ {
+				s := io.NewSectionReader(ra, int64(n/2), int64(n))
+				for {
+					if _, err := s.Read(buf); err != nil {
+						break
+					}
+				}
+			}
+		})
+	}
+}
@@ -5,6 +5,10 @@
 package io_test
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -282,3 +286,225 @@ func ExampleReadAll() {
 	// Output:
 	// Go is a general-purpose language designed with systems programming in mind.
 }
+
+// 以下示例展示各个函数在实际场景中的用法。
+
+func ExampleCopy_checksum() {
+	// 计算哈希时不需要把整个文件读进内存，hash.Hash 本身就是 io.Writer
+	r := strings.NewReader("Clear is better than clever\n")
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%x\n", h.Sum(nil)[:8])
+
+	// Output:
+	// dd0291a68b12b3e5
+}
+
+func ExampleCopyN_header() {
+	// 固定长度的文件头之后是内容，先拷贝文件头，剩下的数据仍然留在 r 中
+	r := strings.NewReader("GOB1payload...")
+	var magic strings.Builder
+	if _, err := io.CopyN(&magic, r, 4); err != nil {
+		log.Fatal(err)
+	}
+	rest, _ := io.ReadAll(r)
+	fmt.Println(magic.String())
+	fmt.Println(string(rest))
+
+	// Output:
+	// GOB1
+	// payload...
+}
+
+func ExampleCopyBuffer_segments() {
+	// 依次拷贝多个日志分段，整个过程只使用一个 32 字节的缓存
+	segments := []io.Reader{
+		strings.NewReader("2023-06-01 server started\n"),
+		strings.NewReader("2023-06-01 request handled\n"),
+		strings.NewReader("2023-06-02 server stopped\n"),
+	}
+	buf := make([]byte, 32)
+	for _, seg := range segments {
+		// 隐藏 WriterTo/ReaderFrom，确保使用的是 buf
+		if _, err := io.CopyBuffer(struct{ io.Writer }{os.Stdout}, struct{ io.Reader }{seg}, buf); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Output:
+	// 2023-06-01 server started
+	// 2023-06-01 request handled
+	// 2023-06-02 server stopped
+}
+
+func ExampleReadFull_lengthPrefixed() {
+	// 读取 <2 字节大端序长度><数据> 格式的消息
+	r := bytes.NewReader([]byte{0x00, 0x05, 'h', 'e', 'l', 'l', 'o', 0x00, 0x03, 'G', 'o'})
+	for {
+		var header [2]byte
+		if _, err := io.ReadFull(r, header[:]); err == io.EOF {
+			break
+		} else if err != nil {
+			log.Fatal(err)
+		}
+		msg := make([]byte, binary.BigEndian.Uint16(header[:]))
+		if _, err := io.ReadFull(r, msg); err != nil {
+			fmt.Println("error:", err)
+			break
+		}
+		fmt.Println(string(msg))
+	}
+
+	// Output:
+	// hello
+	// error: unexpected EOF
+}
+
+func ExampleReadAtLeast_sniff() {
+	// 判断文件类型至少需要前 4 个字节，缓存更大时尽量多读一些，读到的数据之后还要继续使用
+	r := strings.NewReader("%PDF-1.7\n...")
+	buf := make([]byte, 512)
+	n, err := io.ReadAtLeast(r, buf, 4)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if bytes.HasPrefix(buf[:n], []byte("%PDF")) {
+		fmt.Println("application/pdf")
+	}
+
+	// Output:
+	// application/pdf
+}
+
+func ExampleWriteString_csv() {
+	// strings.Builder 实现了 io.StringWriter，io.WriteString 不需要把字符串转换成 []byte
+	var b strings.Builder
+	for i, field := range []string{"id", "proverb", "words"} {
+		if i > 0 {
+			io.WriteString(&b, ",")
+		}
+		io.WriteString(&b, field)
+	}
+	fmt.Println(b.String())
+
+	// Output:
+	// id,proverb,words
+}
+
+func ExampleReadAll_limited() {
+	// 读取不可信的输入（如 HTTP 请求体）时，先用 LimitReader 限制最多读取的字节数
+	body := strings.NewReader(strings.Repeat("x", 100))
+	b, err := io.ReadAll(io.LimitReader(body, 16))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(b))
+
+	// Output:
+	// 16
+}
+
+func ExampleTeeReader_checksum() {
+	// 一边拷贝数据一边计算校验和，数据只读一遍
+	r := strings.NewReader("Clear is better than clever\n")
+	h := sha256.New()
+	var dst strings.Builder
+	if _, err := io.Copy(&dst, io.TeeReader(r, h)); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(dst.String())
+	fmt.Printf("%x\n", h.Sum(nil)[:8])
+
+	// Output:
+	// Clear is better than clever
+	// dd0291a68b12b3e5
+}
+
+func ExampleLimitReader_tooLarge() {
+	// 多读一个字节，读到了就说明输入超出了限制，而不是正好等于限制
+	const limit = 10
+	r := strings.NewReader("Don't communicate by sharing memory")
+	b, _ := io.ReadAll(io.LimitReader(r, limit+1))
+	if len(b) > limit {
+		fmt.Println("input too large")
+	}
+
+	// Output:
+	// input too large
+}
+
+func ExampleNopCloser() {
+	// 函数要求 io.ReadCloser（如 http.Request.Body），而手上只有 io.Reader 时，
+	// 用 NopCloser 包装一下，Close 什么都不做
+	process := func(rc io.ReadCloser) {
+		defer rc.Close()
+		b, _ := io.ReadAll(rc)
+		fmt.Println(string(b))
+	}
+	process(io.NopCloser(strings.NewReader("Errors are values")))
+
+	// Output:
+	// Errors are values
+}
+
+func ExampleMultiReader_header() {
+	// 在正文之前加上邮件头，不需要把正文复制到新的缓存中
+	body := strings.NewReader("Clear is better than clever.\n")
+	header := strings.NewReader("From: gopher@example.com\nSubject: proverb\n\n")
+	if _, err := io.Copy(os.Stdout, io.MultiReader(header, body)); err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// From: gopher@example.com
+	// Subject: proverb
+	//
+	// Clear is better than clever.
+}
+
+func ExampleMultiWriter_log() {
+	// 日志同时输出到终端和内存中（测试时检查内容，或者出错时附加到报告中）
+	var history strings.Builder
+	logger := log.New(io.MultiWriter(os.Stdout, &history), "[app] ", 0)
+	logger.Println("server started")
+	logger.Println("shutting down")
+	fmt.Println(strings.Count(history.String(), "\n"), "lines recorded")
+
+	// Output:
+	// [app] server started
+	// [app] shutting down
+	// 2 lines recorded
+}
+
+func ExamplePipe_json() {
+	// 编码和解码在两个 goroutine 中流式进行，不需要先把整个 JSON 放进内存；
+	// 编码出错时用 CloseWithError 把错误传给读取端
+	r, w := io.Pipe()
+	go func() {
+		enc := json.NewEncoder(w)
+		for _, p := range []string{"Cgo is not Go", "Don't panic"} {
+			if err := enc.Encode(map[string]string{"proverb": p}); err != nil {
+				w.CloseWithError(err)
+				return
+			}
+		}
+		w.Close()
+	}()
+
+	dec := json.NewDecoder(r)
+	for {
+		var v map[string]string
+		if err := dec.Decode(&v); err == io.EOF {
+			break
+		} else if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(v["proverb"])
+	}
+
+	// Output:
+	// Cgo is not Go
+	// Don't panic
+}
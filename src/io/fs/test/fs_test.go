@@ -0,0 +1,76 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ReadFileFromFS 打开 fsys 中的文件并返回 io.ReadCloser，由调用者按需读取。
+// 和 fs.ReadFile 一次性把整个文件读入内存不同，适合处理大文件。
+func ReadFileFromFS(fsys fs.FS, name string) (io.ReadCloser, error) {
+	return fsys.Open(name)
+}
+
+// CopyFromFS 把 fsys 中的文件内容复制到 dst，返回复制的字节数
+func CopyFromFS(dst io.Writer, fsys fs.FS, name string) (int64, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return io.Copy(dst, f)
+}
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCopyFromFS(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "proverbs.txt", strings.Repeat("Errors are values\n", 1000))
+
+	var buf bytes.Buffer
+	n, err := CopyFromFS(&buf, os.DirFS(dir), "proverbs.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(want)) || !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("got %d bytes, want %d bytes equal to os.ReadFile", n, len(want))
+	}
+}
+
+func TestReadFileFromFS(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "proverb.txt", "Don't panic\n")
+
+	rc, err := ReadFileFromFS(os.DirFS(dir), "proverb.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	b, err := io.ReadAll(rc)
+	if err != nil || string(b) != "Don't panic\n" {
+		t.Errorf("got (%q, %v)", b, err)
+	}
+
+	// fs.FS 中的路径不能以 "/" 开头，也不能包含 ".."
+	if _, err := ReadFileFromFS(os.DirFS(dir), "../proverb.txt"); err == nil {
+		t.Error("got nil error for invalid path")
+	}
+}
@@ -0,0 +1,118 @@
+package io_test
+
+import (
+	"bytes"
+	. "io"
+	"testing"
+)
+
+// 模糊测试：go test -fuzz=FuzzReadAtLeast -fuzztime=30s
+// 不加 -fuzz 时只用种子语料运行一遍，与普通测试一样。
+
+// chunkedReader 每次 Read 最多返回 chunk 个字节，覆盖一次读不满的情况
+type chunkedReader struct {
+	r     Reader
+	chunk int
+}
+
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	if len(p) > c.chunk {
+		p = p[:c.chunk]
+	}
+	return c.r.Read(p)
+}
+
+func newFuzzReader(data []byte, chunk uint8) Reader {
+	return &chunkedReader{r: bytes.NewReader(data), chunk: int(chunk)%16 + 1}
+}
+
+func FuzzReadAtLeast(f *testing.F) {
+	f.Add([]byte{}, uint16(0), uint16(0), uint8(1))
+	f.Add([]byte{}, uint16(4), uint16(1), uint8(1))
+	f.Add([]byte{'a'}, uint16(1), uint16(1), uint8(1))
+	f.Add([]byte("abcd"), uint16(8), uint16(4), uint8(2)) // 正好 min 个字节
+	f.Add([]byte("abcd"), uint16(2), uint16(4), uint8(2)) // 缓存比 min 小
+	f.Add([]byte("abcdefgh"), uint16(8), uint16(3), uint8(0))
+
+	f.Fuzz(func(t *testing.T, data []byte, size, min uint16, chunk uint8) {
+		buf := make([]byte, int(size)%1024)
+		n, err := ReadAtLeast(newFuzzReader(data, chunk), buf, int(min))
+
+		if n < 0 || n > len(buf) || n > len(data) {
+			t.Fatalf("n = %d out of range (len(buf) = %d, len(data) = %d)", n, len(buf), len(data))
+		}
+		if !bytes.Equal(buf[:n], data[:n]) {
+			t.Fatalf("read %q, want prefix of %q", buf[:n], data)
+		}
+		switch {
+		case len(buf) < int(min):
+			if err != ErrShortBuffer || n != 0 {
+				t.Fatalf("got (%d, %v), want (0, ErrShortBuffer)", n, err)
+			}
+		case len(data) >= int(min):
+			if err != nil || n < int(min) {
+				t.Fatalf("got (%d, %v) with %d bytes available, want n >= %d", n, err, len(data), min)
+			}
+		case n == 0:
+			if err != EOF {
+				t.Fatalf("got err %v with no data, want EOF", err)
+			}
+		default:
+			if err != ErrUnexpectedEOF {
+				t.Fatalf("got (%d, %v), want ErrUnexpectedEOF", n, err)
+			}
+		}
+	})
+}
+
+func FuzzReadFull(f *testing.F) {
+	f.Add([]byte{}, uint16(0), uint8(1))
+	f.Add([]byte{}, uint16(1), uint8(1))
+	f.Add([]byte{'a'}, uint16(1), uint8(1))
+	f.Add([]byte("abcd"), uint16(4), uint8(3))
+	f.Add([]byte("abcd"), uint16(5), uint8(3))
+
+	f.Fuzz(func(t *testing.T, data []byte, size uint16, chunk uint8) {
+		buf := make([]byte, int(size)%1024)
+		n, err := ReadFull(newFuzzReader(data, chunk), buf)
+
+		if !bytes.Equal(buf[:n], data[:n]) {
+			t.Fatalf("read %q, want prefix of %q", buf[:n], data)
+		}
+		switch {
+		case len(data) >= len(buf):
+			if err != nil || n != len(buf) {
+				t.Fatalf("got (%d, %v), want (%d, nil)", n, err, len(buf))
+			}
+		case len(data) == 0:
+			if err != EOF || n != 0 {
+				t.Fatalf("got (%d, %v), want (0, EOF)", n, err)
+			}
+		default:
+			if err != ErrUnexpectedEOF || n != len(data) {
+				t.Fatalf("got (%d, %v), want (%d, ErrUnexpectedEOF)", n, err, len(data))
+			}
+		}
+	})
+}
+
+func FuzzCopyBuffer(f *testing.F) {
+	f.Add([]byte{}, uint16(1), uint8(1))
+	f.Add([]byte{'a'}, uint16(1), uint8(1))
+	f.Add([]byte("Clear is better than clever"), uint16(4), uint8(7))
+	f.Add(bytes.Repeat([]byte{0}, 5000), uint16(1000), uint8(15))
+
+	f.Fuzz(func(t *testing.T, data []byte, size uint16, chunk uint8) {
+		// 长度为 0 的缓存会让 CopyBuffer panic，这是文档中说明的行为，不在测试范围内
+		buf := make([]byte, int(size)%1024+1)
+		var dst bytes.Buffer
+		// 只暴露 Write，避免 bytes.Buffer 的 ReadFrom 绕过 buf
+		n, err := CopyBuffer(struct{ Writer }{&dst}, newFuzzReader(data, chunk), buf)
+		if err != nil || n != int64(len(data)) {
+			t.Fatalf("got (%d, %v), want (%d, nil)", n, err, len(data))
+		}
+		if !bytes.Equal(dst.Bytes(), data) {
+			t.Fatalf("copied %q, want %q", dst.Bytes(), data)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// readCloser 组合一个 Reader 和一个 Closer
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// gzipReadCloser 关闭时同时关闭 gzip.Reader 和底层的 ReadCloser
+type gzipReadCloser struct {
+	*gzip.Reader
+	rc io.Closer
+}
+
+func (g *gzipReadCloser) Close() error {
+	err := g.Reader.Close()
+	if cerr := g.rc.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// NewAutoGzipReader 通过 Peek 前两个字节判断数据是否是 gzip 格式：
+// 是则用 gzip.NewReader 解压，否则原样读取。
+// Peek 不会消费数据，所以嗅探用到的字节仍然保留在数据流中。
+func NewAutoGzipReader(r io.ReadCloser) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if !bytes.Equal(magic, gzipMagic) {
+		return readCloser{br, r}, nil
+	}
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, err
+	}
+	return &gzipReadCloser{Reader: zr, rc: r}, nil
+}
+
+// closeRecorder 记录 Close 是否被调用
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestAutoGzipReaderCompressed(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte("Channels orchestrate mutexes serialize"))
+	zw.Close()
+
+	src := &closeRecorder{Reader: &buf}
+	r, err := NewAutoGzipReader(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "Channels orchestrate mutexes serialize" {
+		t.Errorf("got %q", b)
+	}
+	r.Close()
+	if !src.closed {
+		t.Error("underlying reader not closed")
+	}
+}
+
+func TestAutoGzipReaderRaw(t *testing.T) {
+	for _, s := range []string{"Cgo is not Go", "\x1f", ""} {
+		src := &closeRecorder{Reader: bytes.NewReader([]byte(s))}
+		r, err := NewAutoGzipReader(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 嗅探用到的字节不能丢失
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != s {
+			t.Errorf("got %q, want %q", b, s)
+		}
+		r.Close()
+		if !src.closed {
+			t.Error("underlying reader not closed")
+		}
+	}
+}
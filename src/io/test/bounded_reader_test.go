@@ -0,0 +1,152 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	ErrBytesExceeded = errors.New("bounded reader: byte limit exceeded")
+	ErrTimeout       = errors.New("bounded reader: read timeout")
+)
+
+// boundedReader 相当于 io.LimitedReader 与超时读取的组合：
+// 读取的总字节数超过 max 时返回 ErrBytesExceeded（而不是 io.EOF），
+// 单次 Read 超过 timeout 仍未返回时返回 ErrTimeout。
+type boundedReader struct {
+	r       io.Reader
+	max     int64 // 允许读取的最大字节数
+	n       int64 // 已经读取的字节数
+	timeout time.Duration
+	err     error // 出错后不再读取底层 Reader
+}
+
+type readResult struct {
+	buf []byte
+	err error
+}
+
+// maxBytes 为负数时按 0 处理
+func NewBoundedReader(r io.Reader, maxBytes int64, timeout time.Duration) io.Reader {
+	if maxBytes < 0 {
+		maxBytes = 0
+	}
+	return &boundedReader{r: r, max: maxBytes, timeout: timeout}
+}
+
+func (b *boundedReader) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	// 多读一个字节，用来判断数据是否超出了限制。
+	// 不直接计算 b.max - b.n + 1，max 为 math.MaxInt64 时会溢出
+	size := int64(len(p))
+	if remain := b.max - b.n; remain < size-1 {
+		size = remain + 1
+	}
+
+	// 超时后底层的 Read 仍可能写入缓存，所以不能直接把 p 交给它
+	ch := make(chan readResult, 1)
+	go func() {
+		buf := make([]byte, size)
+		n, err := b.r.Read(buf)
+		ch <- readResult{buf[:n], err}
+	}()
+
+	timer := time.NewTimer(b.timeout)
+	defer timer.Stop()
+
+	select {
+	case res := <-ch:
+		n := copy(p, res.buf)
+		b.n += int64(n)
+		if b.n > b.max {
+			n -= int(b.n - b.max)
+			b.n = b.max
+			b.err = ErrBytesExceeded
+			return n, b.err
+		}
+		return n, res.err
+	case <-timer.C:
+		b.err = ErrTimeout
+		return 0, b.err
+	}
+}
+
+// slowReader 每次 Read 之前先等待 delay
+type slowReader struct {
+	r     io.Reader
+	delay time.Duration
+}
+
+func (s *slowReader) Read(p []byte) (int, error) {
+	time.Sleep(s.delay)
+	return s.r.Read(p)
+}
+
+func TestBoundedReaderFast(t *testing.T) {
+	r := NewBoundedReader(strings.NewReader("Errors are values"), 17, time.Second)
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "Errors are values" {
+		t.Errorf("got %q", b)
+	}
+}
+
+func TestBoundedReaderMaxInt64(t *testing.T) {
+	r := NewBoundedReader(strings.NewReader("Errors are values"), math.MaxInt64, time.Second)
+	b, err := io.ReadAll(r)
+	if err != nil || string(b) != "Errors are values" {
+		t.Errorf("got (%q, %v), want %q", b, err, "Errors are values")
+	}
+
+	// 负数的限制按 0 处理
+	r = NewBoundedReader(strings.NewReader("Errors"), -5, time.Second)
+	if b, err := io.ReadAll(r); err != ErrBytesExceeded || len(b) != 0 {
+		t.Errorf("got (%q, %v), want (\"\", %v)", b, err, ErrBytesExceeded)
+	}
+}
+
+func TestBoundedReaderBytesExceeded(t *testing.T) {
+	r := NewBoundedReader(strings.NewReader("Errors are values"), 6, time.Second)
+	b, err := io.ReadAll(r)
+	if err != ErrBytesExceeded {
+		t.Errorf("got err %v, want %v", err, ErrBytesExceeded)
+	}
+	if string(b) != "Errors" {
+		t.Errorf("got %q, want %q", b, "Errors")
+	}
+}
+
+func TestBoundedReaderTimeout(t *testing.T) {
+	src := &slowReader{r: strings.NewReader("Don't panic"), delay: 100 * time.Millisecond}
+	r := NewBoundedReader(src, 1024, 10*time.Millisecond)
+	if _, err := io.ReadAll(r); err != ErrTimeout {
+		t.Errorf("got err %v, want %v", err, ErrTimeout)
+	}
+}
+
+func TestBoundedReaderBoth(t *testing.T) {
+	// 两个限制同时设置时，先触发的那个生效
+	src := &slowReader{r: strings.NewReader("Don't panic"), delay: 100 * time.Millisecond}
+	r := NewBoundedReader(src, 4, 10*time.Millisecond)
+	if _, err := io.ReadAll(r); err != ErrTimeout {
+		t.Errorf("got err %v, want %v", err, ErrTimeout)
+	}
+
+	src = &slowReader{r: strings.NewReader("Don't panic"), delay: time.Millisecond}
+	r = NewBoundedReader(src, 4, time.Second)
+	if _, err := io.ReadAll(r); err != ErrBytesExceeded {
+		t.Errorf("got err %v, want %v", err, ErrBytesExceeded)
+	}
+}
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// BufferSeeker 是支持 Seek 的内存 Writer。
+// bytes.Buffer 只能在末尾追加，BufferSeeker 可以定位到任意位置覆盖写入。
+// 定位到末尾之后再写入时，中间的空洞用 0 填充，和文件的行为一致。
+type BufferSeeker struct {
+	buf []byte
+	off int64
+}
+
+func NewBufferSeeker() *BufferSeeker {
+	return &BufferSeeker{}
+}
+
+func (b *BufferSeeker) Write(p []byte) (int, error) {
+	end := b.off + int64(len(p))
+	if end > int64(len(b.buf)) {
+		if end > int64(cap(b.buf)) {
+			buf := make([]byte, end, 2*end)
+			copy(buf, b.buf)
+			b.buf = buf
+		} else {
+			// buf 只会变长，len 之后的容量都是 make 时清零的，可以直接使用
+			b.buf = b.buf[:end]
+		}
+	}
+	n := copy(b.buf[b.off:], p)
+	b.off += int64(n)
+	return n, nil
+}
+
+func (b *BufferSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	default:
+		return 0, errWhence
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += b.off
+	case io.SeekEnd:
+		offset += int64(len(b.buf))
+	}
+	if offset < 0 {
+		return 0, errOffset
+	}
+	b.off = offset
+	return offset, nil
+}
+
+// Bytes 返回底层存储，在下一次 Write 之前有效
+func (b *BufferSeeker) Bytes() []byte {
+	return b.buf
+}
+
+func TestBufferSeeker(t *testing.T) {
+	b := NewBufferSeeker()
+	io.WriteString(b, "0123456789")
+	b.Seek(5, io.SeekStart)
+	io.WriteString(b, "abc")
+
+	if got, want := b.Bytes(), []byte("01234abc89"); !bytes.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// 写入超出当前长度时会扩展
+	b.Seek(-1, io.SeekEnd)
+	io.WriteString(b, "XYZ")
+	if got, want := b.Bytes(), []byte("01234abc8XYZ"); !bytes.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestBufferSeekerZeroExtend(t *testing.T) {
+	b := NewBufferSeeker()
+	io.WriteString(b, "ab")
+	b.Seek(3, io.SeekCurrent)
+	io.WriteString(b, "cd")
+
+	if got, want := b.Bytes(), []byte("ab\x00\x00\x00cd"); !bytes.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
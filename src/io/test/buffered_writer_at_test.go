@@ -0,0 +1,123 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sort"
+	"testing"
+)
+
+var ErrOverlap = errors.New("buffered writer at: overlapping write")
+
+type writeAtSegment struct {
+	off  int64
+	data []byte
+}
+
+// BufferedWriterAt 接收任意顺序的 WriteAt，先缓存起来，
+// Flush 时按偏移量从小到大顺序写入底层 Writer，没有写到的部分用 0 填充，总长度为 size。
+// 适合把随机写入的数据（如分块下载）输出到只支持顺序写入的目标。
+type BufferedWriterAt struct {
+	w        io.Writer
+	size     int64
+	segments []writeAtSegment
+}
+
+func NewBufferedWriterAt(w io.Writer, size int64) *BufferedWriterAt {
+	return &BufferedWriterAt{w: w, size: size}
+}
+
+// WriteAt 缓存 p，与已经缓存的数据有重叠时返回 ErrOverlap
+func (b *BufferedWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 || off+int64(len(p)) > b.size {
+		return 0, errOffset
+	}
+	end := off + int64(len(p))
+	for _, s := range b.segments {
+		if off < s.off+int64(len(s.data)) && s.off < end {
+			return 0, ErrOverlap
+		}
+	}
+	b.segments = append(b.segments, writeAtSegment{off: off, data: bytes.Clone(p)})
+	return len(p), nil
+}
+
+// Flush 按顺序写出所有缓存的数据
+func (b *BufferedWriterAt) Flush() error {
+	sort.Slice(b.segments, func(i, j int) bool {
+		return b.segments[i].off < b.segments[j].off
+	})
+
+	var pos int64
+	zeros := make([]byte, 4096)
+	fill := func(end int64) error {
+		for pos < end {
+			n := end - pos
+			if n > int64(len(zeros)) {
+				n = int64(len(zeros))
+			}
+			if _, err := b.w.Write(zeros[:n]); err != nil {
+				return err
+			}
+			pos += n
+		}
+		return nil
+	}
+
+	for _, s := range b.segments {
+		if err := fill(s.off); err != nil {
+			return err
+		}
+		if _, err := b.w.Write(s.data); err != nil {
+			return err
+		}
+		pos += int64(len(s.data))
+	}
+	if err := fill(b.size); err != nil {
+		return err
+	}
+	b.segments = nil
+	return nil
+}
+
+func TestBufferedWriterAt(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewBufferedWriterAt(&buf, 120)
+
+	a := bytes.Repeat([]byte{'a'}, 10)
+	b := bytes.Repeat([]byte{'b'}, 20)
+	c := bytes.Repeat([]byte{'c'}, 10)
+	w.WriteAt(a, 0)
+	w.WriteAt(c, 100)
+	w.WriteAt(b, 50)
+	if buf.Len() != 0 {
+		t.Fatalf("data written before Flush: %d bytes", buf.Len())
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := make([]byte, 120)
+	copy(want[0:], a)
+	copy(want[50:], b)
+	copy(want[100:], c)
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("got %q, want %q", buf.Bytes(), want)
+	}
+}
+
+func TestBufferedWriterAtOverlap(t *testing.T) {
+	w := NewBufferedWriterAt(io.Discard, 100)
+	w.WriteAt([]byte("0123456789"), 10)
+	if _, err := w.WriteAt([]byte("xx"), 19); err != ErrOverlap {
+		t.Errorf("got err %v, want %v", err, ErrOverlap)
+	}
+	// 紧挨着的写入不算重叠
+	if _, err := w.WriteAt([]byte("xx"), 20); err != nil {
+		t.Errorf("got err %v, want nil", err)
+	}
+	if _, err := w.WriteAt([]byte("xx"), 99); err != errOffset {
+		t.Errorf("got err %v, want %v", err, errOffset)
+	}
+}
@@ -0,0 +1,91 @@
+package test
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ByteHistogramWriter 统计写入的每个字节值出现的次数，只统计底层 Writer 实际接收的部分。
+// Write 和 Histogram 可以在不同的 goroutine 中同时调用。
+type ByteHistogramWriter struct {
+	w io.Writer
+
+	mu   sync.Mutex
+	hist [256]uint64
+}
+
+func NewByteHistogramWriter(w io.Writer) *ByteHistogramWriter {
+	return &ByteHistogramWriter{w: w}
+}
+
+func (h *ByteHistogramWriter) Write(p []byte) (int, error) {
+	n, err := h.w.Write(p)
+	// 先在局部变量中计数，缩短持有锁的时间
+	var local [256]uint64
+	for _, c := range p[:n] {
+		local[c]++
+	}
+	h.mu.Lock()
+	for i, c := range local {
+		h.hist[i] += c
+	}
+	h.mu.Unlock()
+	return n, err
+}
+
+// Histogram 返回当前统计结果的副本
+func (h *ByteHistogramWriter) Histogram() [256]uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.hist
+}
+
+func TestByteHistogramWriter(t *testing.T) {
+	h := NewByteHistogramWriter(io.Discard)
+	io.WriteString(h, "hello, ")
+	io.WriteString(h, "world")
+
+	want := map[byte]uint64{'h': 1, 'e': 1, 'l': 3, 'o': 2, ',': 1, ' ': 1, 'w': 1, 'r': 1, 'd': 1}
+	hist := h.Histogram()
+	for c := 0; c < 256; c++ {
+		if hist[c] != want[byte(c)] {
+			t.Errorf("byte %q: got %d, want %d", c, hist[c], want[byte(c)])
+		}
+	}
+}
+
+// go test -race -run ByteHistogramWriterConcurrent
+func TestByteHistogramWriterConcurrent(t *testing.T) {
+	h := NewByteHistogramWriter(io.Discard)
+	line := strings.Repeat("ab", 50)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				io.WriteString(h, line)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				hist := h.Histogram()
+				// 每次 Write 整体计入，a 和 b 的次数总是相等
+				if hist['a'] != hist['b'] {
+					t.Errorf("got a=%d b=%d", hist['a'], hist['b'])
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	hist := h.Histogram()
+	if hist['a'] != 4*100*50 || hist['b'] != 4*100*50 {
+		t.Errorf("got a=%d b=%d, want %d each", hist['a'], hist['b'], 4*100*50)
+	}
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+var errInvalidUnreadByte = errors.New("byte reader: invalid use of UnreadByte")
+
+// byteReader 为任意 io.Reader 实现 io.ByteScanner，每次只从底层读取一个字节，不会预读。
+// 单字节的缓存放在结构体中，而不是在 ReadByte 中声明局部变量：
+// 局部变量传给接口方法 Read 会逃逸到堆上，每次调用都会产生一次内存分配。
+type byteReader struct {
+	r      io.Reader
+	buf    [1]byte
+	unread bool // UnreadByte 之后，下一次 ReadByte 直接返回 buf[0]
+	valid  bool // buf[0] 是否是上一次成功读取的字节
+}
+
+func NewByteReader(r io.Reader) io.ByteReader {
+	return &byteReader{r: r}
+}
+
+func (b *byteReader) ReadByte() (byte, error) {
+	if b.unread {
+		b.unread = false
+		b.valid = true
+		return b.buf[0], nil
+	}
+	b.valid = false
+	for {
+		n, err := b.r.Read(b.buf[:])
+		if n == 1 {
+			b.valid = true
+			return b.buf[0], nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// UnreadByte 只能撤销最近一次成功的 ReadByte
+func (b *byteReader) UnreadByte() error {
+	if !b.valid {
+		return errInvalidUnreadByte
+	}
+	b.valid = false
+	b.unread = true
+	return nil
+}
+
+func TestByteReaderUnread(t *testing.T) {
+	r := NewByteReader(strings.NewReader("Go")).(io.ByteScanner)
+
+	c1, _ := r.ReadByte()
+	if err := r.UnreadByte(); err != nil {
+		t.Fatal(err)
+	}
+	c2, _ := r.ReadByte()
+	if c1 != 'G' || c2 != 'G' {
+		t.Errorf("got %q and %q, want 'G' twice", c1, c2)
+	}
+
+	c3, _ := r.ReadByte()
+	if c3 != 'o' {
+		t.Errorf("got %q, want 'o'", c3)
+	}
+	if _, err := r.ReadByte(); err != io.EOF {
+		t.Errorf("got err %v, want EOF", err)
+	}
+	// 读取失败之后不能 UnreadByte，连续两次 UnreadByte 也不行
+	if err := r.UnreadByte(); err != errInvalidUnreadByte {
+		t.Errorf("got err %v, want %v", err, errInvalidUnreadByte)
+	}
+}
+
+func TestByteReaderAllocs(t *testing.T) {
+	src := strings.NewReader(strings.Repeat("x", 1000))
+	r := NewByteReader(src)
+	allocs := testing.AllocsPerRun(100, func() {
+		r.ReadByte()
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocs per ReadByte, want 0", allocs)
+	}
+}
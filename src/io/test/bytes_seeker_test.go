@@ -0,0 +1,103 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+// 与 io 包中 SectionReader 使用的错误保持一致
+var (
+	errWhence = errors.New("Seek: invalid whence")
+	errOffset = errors.New("Seek: invalid offset")
+)
+
+// bytesSeeker 直接在 b 上读取和定位，不会复制 b。
+// 与 bytes.NewReader 相比，只暴露最小的 io.ReadSeeker 接口。
+type bytesSeeker struct {
+	b   []byte
+	off int64
+}
+
+func NewBytesSeeker(b []byte) io.ReadSeeker {
+	return &bytesSeeker{b: b}
+}
+
+func (s *bytesSeeker) Read(p []byte) (int, error) {
+	if s.off >= int64(len(s.b)) {
+		return 0, io.EOF
+	}
+	n := copy(p, s.b[s.off:])
+	s.off += int64(n)
+	return n, nil
+}
+
+// Seek 允许定位到数据末尾之后，此时下一次 Read 直接返回 io.EOF
+func (s *bytesSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	default:
+		return 0, errWhence
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += s.off
+	case io.SeekEnd:
+		offset += int64(len(s.b))
+	}
+	if offset < 0 {
+		return 0, errOffset
+	}
+	s.off = offset
+	return offset, nil
+}
+
+func TestBytesSeeker(t *testing.T) {
+	data := []byte("Clear is better than clever")
+	s := NewBytesSeeker(data)
+
+	p := make([]byte, len(data))
+	if _, err := io.ReadFull(s, p); err != nil {
+		t.Fatal(err)
+	}
+	if string(p) != string(data) {
+		t.Errorf("got %q, want %q", p, data)
+	}
+
+	// 回到开头再读一次
+	if _, err := s.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(s, p[:5]); err != nil || string(p[:5]) != "Clear" {
+		t.Errorf("got (%q, %v), want %q", p[:5], err, "Clear")
+	}
+
+	if off, err := s.Seek(4, io.SeekCurrent); err != nil || off != 9 {
+		t.Errorf("SeekCurrent: got (%d, %v), want 9", off, err)
+	}
+
+	off, err := s.Seek(-1, io.SeekEnd)
+	if err != nil || off != int64(len(data)-1) {
+		t.Errorf("SeekEnd: got (%d, %v), want %d", off, err, len(data)-1)
+	}
+	if b, _ := io.ReadAll(s); string(b) != "r" {
+		t.Errorf("got %q after seeking to -1 from end, want %q", b, "r")
+	}
+}
+
+func TestBytesSeekerBounds(t *testing.T) {
+	s := NewBytesSeeker([]byte("Don't panic"))
+
+	if _, err := s.Seek(-1, io.SeekStart); err != errOffset {
+		t.Errorf("got err %v, want %v", err, errOffset)
+	}
+	if _, err := s.Seek(0, 3); err != errWhence {
+		t.Errorf("got err %v, want %v", err, errWhence)
+	}
+
+	// 定位到末尾之后是合法的，读取时直接返回 io.EOF
+	if off, err := s.Seek(100, io.SeekStart); err != nil || off != 100 {
+		t.Errorf("got (%d, %v), want 100", off, err)
+	}
+	if n, err := s.Read(make([]byte, 4)); n != 0 || err != io.EOF {
+		t.Errorf("got (%d, %v), want (0, EOF)", n, err)
+	}
+}
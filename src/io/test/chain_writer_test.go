@@ -0,0 +1,120 @@
+package test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+)
+
+// chainWriter 是 ChainWriters 生成的流水线，layers 从外到内排列。
+// 像 gzip.Writer、base64 编码器这样的 Writer 需要 Close 才会写出最后的数据，
+// 所以 Close 从外到内依次关闭每一层实现了 io.Closer 的 Writer（不包括最终的目标 Writer）。
+type chainWriter struct {
+	layers []io.Writer
+}
+
+func (c *chainWriter) Write(p []byte) (int, error) {
+	return c.layers[0].Write(p)
+}
+
+func (c *chainWriter) Close() error {
+	for _, w := range c.layers {
+		if cl, ok := w.(io.Closer); ok {
+			if err := cl.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ChainWriters 把多个 Writer 包装函数组合成一条流水线。
+// 写入的数据依次经过 writers[0]、writers[1] ... 的处理，最后写入目标 Writer。
+// 返回的 Writer 实现了 io.Closer，用完后需要 Close。
+func ChainWriters(writers ...func(io.Writer) io.Writer) func(io.Writer) io.Writer {
+	return func(dst io.Writer) io.Writer {
+		layers := make([]io.Writer, len(writers))
+		w := dst
+		for i := len(writers) - 1; i >= 0; i-- {
+			w = writers[i](w)
+			layers[i] = w
+		}
+		if len(layers) == 0 {
+			return dst
+		}
+		return &chainWriter{layers: layers}
+	}
+}
+
+// lineWrapper 每写出 width 个字节插入一个换行
+type lineWrapper struct {
+	w     io.Writer
+	width int
+	col   int
+}
+
+func (l *lineWrapper) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		if l.col == l.width {
+			if _, err := l.w.Write([]byte{'\n'}); err != nil {
+				return n, err
+			}
+			l.col = 0
+		}
+		m := l.width - l.col
+		if m > len(p) {
+			m = len(p)
+		}
+		m, err := l.w.Write(p[:m])
+		n += m
+		l.col += m
+		if err != nil {
+			return n, err
+		}
+		p = p[m:]
+	}
+	return n, nil
+}
+
+func TestChainWriters(t *testing.T) {
+	pipeline := ChainWriters(
+		func(w io.Writer) io.Writer { return gzip.NewWriter(w) },
+		func(w io.Writer) io.Writer { return base64.NewEncoder(base64.StdEncoding, w) },
+		func(w io.Writer) io.Writer { return &lineWrapper{w: w, width: 76} },
+	)
+
+	data := strings.Repeat("Clear is better than clever. ", 10) + "Don't panic."
+	var out bytes.Buffer
+	w := pipeline(&out)
+	io.WriteString(w, data)
+	if err := w.(io.Closer).Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(out.String(), "\n")
+	for i, l := range lines {
+		if len(l) > 76 || (i < len(lines)-1 && len(l) != 76) {
+			t.Errorf("line %d has %d chars", i, len(l))
+		}
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.Join(lines, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != data {
+		t.Errorf("got %q, want %q", b, data)
+	}
+}
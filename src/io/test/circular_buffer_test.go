@@ -0,0 +1,92 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+)
+
+// CircularDiagnosticBuffer 是一个环形缓存，只保留最近写入的 capacity 个字节，
+// 更早的数据会被直接覆盖。适合在程序出错时输出最近的日志用于排查问题。
+type CircularDiagnosticBuffer struct {
+	mu   sync.Mutex
+	buf  []byte
+	pos  int  // 下一次写入的位置
+	full bool // 是否已经写满过一圈
+}
+
+func NewCircularDiagnosticBuffer(capacity int) *CircularDiagnosticBuffer {
+	return &CircularDiagnosticBuffer{buf: make([]byte, capacity)}
+}
+
+func (c *CircularDiagnosticBuffer) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := len(p)
+	if len(c.buf) == 0 {
+		return n, nil
+	}
+	// 超过容量的部分只需要保留最后 capacity 个字节
+	if len(p) > len(c.buf) {
+		p = p[len(p)-len(c.buf):]
+	}
+	m := copy(c.buf[c.pos:], p)
+	if m < len(p) {
+		copy(c.buf, p[m:])
+		c.full = true
+	}
+	c.pos = (c.pos + len(p)) % len(c.buf)
+	if c.pos == 0 && len(p) > 0 {
+		c.full = true
+	}
+	return n, nil
+}
+
+// Snapshot 按写入顺序返回当前缓存内容的副本
+func (c *CircularDiagnosticBuffer) Snapshot() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.full {
+		return bytes.Clone(c.buf[:c.pos])
+	}
+	out := make([]byte, 0, len(c.buf))
+	out = append(out, c.buf[c.pos:]...)
+	return append(out, c.buf[:c.pos]...)
+}
+
+func TestCircularDiagnosticBuffer(t *testing.T) {
+	c := NewCircularDiagnosticBuffer(10)
+	io.WriteString(c, "abc")
+	if got := string(c.Snapshot()); got != "abc" {
+		t.Errorf("got %q, want %q", got, "abc")
+	}
+
+	// 写入 3 倍的容量，每次 7 个字节，多次跨越环的边界
+	var all bytes.Buffer
+	all.WriteString("abc")
+	for i := 0; i < 4; i++ {
+		chunk := []byte{'0' + byte(i), 'b', 'c', 'd', 'e', 'f', 'g'}
+		c.Write(chunk)
+		all.Write(chunk)
+		want := all.Bytes()[all.Len()-10:]
+		if got := c.Snapshot(); !bytes.Equal(got, want) {
+			t.Errorf("after write %d: got %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestCircularDiagnosticBufferLargeWrite(t *testing.T) {
+	c := NewCircularDiagnosticBuffer(8)
+	io.WriteString(c, "xyz")
+	// 一次写入超过容量的数据
+	n, _ := io.WriteString(c, "Channels orchestrate")
+	if n != 20 {
+		t.Errorf("got n = %d, want 20", n)
+	}
+	if got := string(c.Snapshot()); got != "hestrate" {
+		t.Errorf("got %q, want %q", got, "hestrate")
+	}
+}
@@ -0,0 +1,150 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var ErrDivergence = errors.New("comparison reader: streams diverge")
+
+// DivergenceError 记录两个流第一次出现不同的位置。
+// 其中一个流提前结束时，对应的字节值为 -1。
+type DivergenceError struct {
+	Offset int64
+	A, B   int
+}
+
+func (e *DivergenceError) Error() string {
+	return fmt.Sprintf("%v at offset %d: a=%d b=%d", ErrDivergence, e.Offset, e.A, e.B)
+}
+
+func (e *DivergenceError) Unwrap() error {
+	return ErrDivergence
+}
+
+// ComparisonReader 同时读取 a 和 b，返回 a 的数据，
+// 两者在某个位置不同（包括一个流比另一个短）时返回 *DivergenceError。
+// 读取 b 时出现 io.EOF 以外的错误不是内容不同，原样返回这个错误。
+type ComparisonReader struct {
+	a, b io.Reader
+	buf  []byte
+	off  int64
+}
+
+func NewComparisonReader(a, b io.Reader) *ComparisonReader {
+	return &ComparisonReader{a: a, b: b}
+}
+
+func (c *ComparisonReader) Read(p []byte) (int, error) {
+	n, err := c.a.Read(p)
+	if n > 0 {
+		if cap(c.buf) < n {
+			c.buf = make([]byte, n)
+		}
+		m, errB := io.ReadFull(c.b, c.buf[:n])
+		if errB == io.ErrUnexpectedEOF || errB == io.EOF {
+			errB = nil // b 比 a 短，下面按内容不同处理
+		}
+		for i := 0; i < n; i++ {
+			if i >= m {
+				if errB != nil {
+					c.off += int64(i)
+					return i, errB
+				}
+				return i, &DivergenceError{Offset: c.off + int64(i), A: int(p[i]), B: -1}
+			}
+			if p[i] != c.buf[i] {
+				return i, &DivergenceError{Offset: c.off + int64(i), A: int(p[i]), B: int(c.buf[i])}
+			}
+		}
+		c.off += int64(n)
+	}
+
+	if err == io.EOF {
+		// a 已经结束，b 也必须同时结束
+		var one [1]byte
+		m, errB := io.ReadFull(c.b, one[:])
+		if m > 0 {
+			return n, &DivergenceError{Offset: c.off, A: -1, B: int(one[0])}
+		}
+		if errB != io.EOF {
+			return n, errB
+		}
+	}
+	return n, err
+}
+
+func TestComparisonReaderIdentical(t *testing.T) {
+	s := "Clear is better than clever"
+	r := NewComparisonReader(strings.NewReader(s), iotest.HalfReader(strings.NewReader(s)))
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != s {
+		t.Errorf("got %q, want %q", b, s)
+	}
+}
+
+func TestComparisonReaderDiverge(t *testing.T) {
+	r := NewComparisonReader(strings.NewReader("Errors are values"), strings.NewReader("Errors are va1ues"))
+	b, err := io.ReadAll(r)
+
+	var de *DivergenceError
+	if !errors.As(err, &de) || !errors.Is(err, ErrDivergence) {
+		t.Fatalf("got err %v, want DivergenceError", err)
+	}
+	if de.Offset != 13 || de.A != 'l' || de.B != '1' {
+		t.Errorf("got %+v, want offset 13, a='l', b='1'", de)
+	}
+	// 返回的数据只到出现差异的位置为止
+	if string(b) != "Errors are va" {
+		t.Errorf("got %q, want %q", b, "Errors are va")
+	}
+}
+
+func TestComparisonReaderLength(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		offset int64
+		va, vb int
+	}{
+		{"Don't panic", "Don't", 5, ' ', -1},
+		{"Don't", "Don't panic", 5, -1, ' '},
+	}
+	for _, tt := range tests {
+		_, err := io.ReadAll(NewComparisonReader(strings.NewReader(tt.a), strings.NewReader(tt.b)))
+		var de *DivergenceError
+		if !errors.As(err, &de) {
+			t.Errorf("(%q, %q): got err %v, want DivergenceError", tt.a, tt.b, err)
+			continue
+		}
+		if de.Offset != tt.offset || de.A != tt.va || de.B != tt.vb {
+			t.Errorf("(%q, %q): got %+v", tt.a, tt.b, de)
+		}
+	}
+}
+
+func TestComparisonReaderErrorB(t *testing.T) {
+	errRead := errors.New("read failed")
+	tests := []struct {
+		a    string
+		b    io.Reader
+		want string
+	}{
+		// b 读到一半出错
+		{"Don't panic", io.MultiReader(strings.NewReader("Don't"), errorReader{errRead}), "Don't"},
+		// a 结束后检查 b 是否结束时出错
+		{"Don't", io.MultiReader(strings.NewReader("Don't"), errorReader{errRead}), "Don't"},
+	}
+	for _, tt := range tests {
+		got, err := io.ReadAll(NewComparisonReader(strings.NewReader(tt.a), tt.b))
+		if err != errRead || string(got) != tt.want {
+			t.Errorf("%q: got (%q, %v), want (%q, %v)", tt.a, got, err, tt.want, errRead)
+		}
+	}
+}
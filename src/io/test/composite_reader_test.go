@@ -0,0 +1,92 @@
+package test
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// CompositeReader 按顺序读取一组 io.Reader，效果与 io.MultiReader 相同，
+// 区别在于 Reader 列表可以在读取过程中用 Append 追加，Reset 可以回到第一个 Reader 重新开始。
+// Reset 只移动当前位置，已经读过的 Reader 需要自己支持从头读取（如实现了 io.Seeker 并已经 Seek 回开头），
+// 否则重新读取时得到的是它们剩余的数据。
+type CompositeReader struct {
+	readers []io.Reader
+	cur     int // 当前正在读取的 Reader
+}
+
+func NewCompositeReader(readers []io.Reader) *CompositeReader {
+	// 复制一份，之后对调用者的切片的修改不影响 CompositeReader
+	return &CompositeReader{readers: append([]io.Reader(nil), readers...)}
+}
+
+func (c *CompositeReader) Read(p []byte) (int, error) {
+	for c.cur < len(c.readers) {
+		n, err := c.readers[c.cur].Read(p)
+		if err == io.EOF {
+			c.cur++
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+	return 0, io.EOF
+}
+
+// Append 把 r 追加到列表末尾。即使之前的 Read 已经返回了 io.EOF，之后也能继续读到 r 的数据
+func (c *CompositeReader) Append(r io.Reader) {
+	c.readers = append(c.readers, r)
+}
+
+// Reset 把当前位置移回第一个 Reader
+func (c *CompositeReader) Reset() {
+	c.cur = 0
+}
+
+func TestCompositeReader(t *testing.T) {
+	r := NewCompositeReader([]io.Reader{
+		strings.NewReader("Clear is "),
+		strings.NewReader("better "),
+	})
+
+	p := make([]byte, 6)
+	n, _ := io.ReadFull(r, p)
+	if string(p[:n]) != "Clear " {
+		t.Fatalf("got %q", p[:n])
+	}
+
+	// 读到一半时追加
+	r.Append(strings.NewReader("than "))
+	r.Append(strings.NewReader("clever"))
+
+	rest, err := io.ReadAll(r)
+	if err != nil || string(rest) != "is better than clever" {
+		t.Errorf("got (%q, %v)", rest, err)
+	}
+
+	// 读完之后追加，之前已经返回过 io.EOF
+	r.Append(strings.NewReader("!"))
+	rest, _ = io.ReadAll(r)
+	if string(rest) != "!" {
+		t.Errorf("got %q after EOF, want %q", rest, "!")
+	}
+}
+
+func TestCompositeReaderReset(t *testing.T) {
+	readers := []io.Reader{strings.NewReader("Don't "), strings.NewReader("panic")}
+	r := NewCompositeReader(readers)
+	if b, _ := io.ReadAll(r); string(b) != "Don't panic" {
+		t.Fatalf("got %q", b)
+	}
+
+	// strings.Reader 支持 Seek，把它们移回开头之后 Reset 就能从头再读一遍
+	for _, sr := range readers {
+		sr.(io.Seeker).Seek(0, io.SeekStart)
+	}
+	r.Reset()
+	if b, _ := io.ReadAll(r); string(b) != "Don't panic" {
+		t.Errorf("got %q after Reset", b)
+	}
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// NewConcatReader 依次读取 readers，相邻的两个之间插入分隔符 sep。
+// sep 实现了 io.Seeker 时每次插入前 Seek 回开头重新读取；
+// 否则第一次插入时把 sep 读完缓存下来，之后每次插入缓存的内容。
+func NewConcatReader(sep io.Reader, readers ...io.Reader) io.Reader {
+	src := &sepSource{sep: sep}
+	var parts []io.Reader
+	for i, r := range readers {
+		if i > 0 {
+			parts = append(parts, &sepReader{src: src})
+		}
+		parts = append(parts, r)
+	}
+	return io.MultiReader(parts...)
+}
+
+// sepSource 由同一个 ConcatReader 中所有的 sepReader 共享
+type sepSource struct {
+	sep    io.Reader
+	cached []byte // 不能 Seek 的 sep 读完后的内容
+	loaded bool
+}
+
+// reader 返回从头开始读取分隔符的 Reader
+func (s *sepSource) reader() (io.Reader, error) {
+	if seeker, ok := s.sep.(io.ReadSeeker); ok {
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return seeker, nil
+	}
+	if !s.loaded {
+		b, err := io.ReadAll(s.sep)
+		if err != nil {
+			return nil, err
+		}
+		s.cached, s.loaded = b, true
+	}
+	return bytes.NewReader(s.cached), nil
+}
+
+// sepReader 在第一次 Read 时才准备分隔符，保证 Seek 发生在上一个 sepReader 读完之后
+type sepReader struct {
+	src *sepSource
+	r   io.Reader
+}
+
+func (s *sepReader) Read(p []byte) (int, error) {
+	if s.r == nil {
+		r, err := s.src.reader()
+		if err != nil {
+			return 0, err
+		}
+		s.r = r
+	}
+	return s.r.Read(p)
+}
+
+func TestConcatReader(t *testing.T) {
+	var readers []io.Reader
+	for _, s := range []string{"s1", "s2", "s3", "s4", "s5"} {
+		readers = append(readers, strings.NewReader(s))
+	}
+	got, err := io.ReadAll(NewConcatReader(bytes.NewReader([]byte{','}), readers...))
+	if err != nil || string(got) != "s1,s2,s3,s4,s5" {
+		t.Errorf("got (%q, %v), want %q", got, err, "s1,s2,s3,s4,s5")
+	}
+}
+
+func TestConcatReaderNonSeekable(t *testing.T) {
+	sep := onlyReader{strings.NewReader(" | ")}
+	r := NewConcatReader(sep, strings.NewReader("a"), strings.NewReader("b"), strings.NewReader("c"))
+	got, _ := io.ReadAll(r)
+	if string(got) != "a | b | c" {
+		t.Errorf("got %q", got)
+	}
+}
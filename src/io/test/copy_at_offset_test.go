@@ -0,0 +1,97 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// CopyAtOffset 从 src 读取数据直到 io.EOF，从 dst 的 offset 处开始依次写入，返回写入的字节数。
+// 与 CopyTo 相反，它把顺序的流写到随机访问的目标的指定位置，如把分块下载的某一块写入文件。
+// 拷贝用的缓存与 CopyTo 共用同一个 sync.Pool。
+func CopyAtOffset(dst io.WriterAt, src io.Reader, offset int64) (int64, error) {
+	bp := copyToPool.Get().(*[]byte)
+	defer copyToPool.Put(bp)
+	buf := *bp
+
+	var written int64
+	for {
+		nr, rerr := src.Read(buf)
+		if nr > 0 {
+			nw, werr := dst.WriteAt(buf[:nr], offset+written)
+			written += int64(nw)
+			if werr != nil {
+				return written, werr
+			}
+			if nw != nr {
+				return written, io.ErrShortWrite
+			}
+		}
+		if rerr == io.EOF {
+			return written, nil
+		}
+		if rerr != nil {
+			return written, rerr
+		}
+	}
+}
+
+// sliceWriterAt 是基于预先分配的 []byte 的 WriterAt，超出范围的部分写不进去
+type sliceWriterAt []byte
+
+func (s sliceWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 || off > int64(len(s)) {
+		return 0, errOffset
+	}
+	n := copy(s[off:], p)
+	if n < len(p) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+func TestCopyAtOffset(t *testing.T) {
+	tests := []struct {
+		offset int64
+		data   string
+		want   string
+	}{
+		{0, "Clear", "Clear..............."},
+		{6, "is", "......is............"},
+		{15, "clever", "...............cleve"},
+	}
+	for _, tt := range tests {
+		dst := sliceWriterAt(bytes.Repeat([]byte("."), 20))
+		n, err := CopyAtOffset(dst, strings.NewReader(tt.data), tt.offset)
+		if string(dst) != tt.want {
+			t.Errorf("offset %d: got %q, want %q", tt.offset, dst, tt.want)
+		}
+		// 超出末尾时只写进去一部分
+		wantN := int64(len(tt.data))
+		if tt.offset+wantN > int64(len(dst)) {
+			wantN = int64(len(dst)) - tt.offset
+			if err != io.ErrShortWrite {
+				t.Errorf("offset %d: got err %v, want %v", tt.offset, err, io.ErrShortWrite)
+			}
+		} else if err != nil {
+			t.Errorf("offset %d: got err %v", tt.offset, err)
+		}
+		if n != wantN {
+			t.Errorf("offset %d: got n = %d, want %d", tt.offset, n, wantN)
+		}
+	}
+}
+
+func TestCopyAtOffsetLarge(t *testing.T) {
+	// 数据比缓存大，需要多次 WriteAt
+	data := bytes.Repeat([]byte("Don't panic. "), 10000)
+	dst := make(sliceWriterAt, 100+len(data))
+	n, err := CopyAtOffset(dst, bytes.NewReader(data), 100)
+	if err != nil || n != int64(len(data)) {
+		t.Fatalf("got (%d, %v)", n, err)
+	}
+	if !bytes.Equal(dst[100:], data) || !bytes.Equal(dst[:100], make([]byte, 100)) {
+		t.Error("content mismatch")
+	}
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+var copyToPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 32<<10)
+		return &b
+	},
+}
+
+// CopyTo 从 r 的 off 处开始读取 n 个字节写入 dst，返回写入的字节数。
+// 与 io.CopyN 不同，它不依赖也不改变读取位置，多个 goroutine 可以同时从同一个 ReaderAt 拷贝不同的区间。
+// 数据不够 n 个字节时返回 io.EOF，与 io.CopyN 一致。
+func CopyTo(dst io.Writer, r io.ReaderAt, off int64, n int64) (int64, error) {
+	bp := copyToPool.Get().(*[]byte)
+	defer copyToPool.Put(bp)
+	buf := *bp
+
+	var written int64
+	for written < n {
+		p := buf
+		if remain := n - written; remain < int64(len(p)) {
+			p = p[:remain]
+		}
+		nr, rerr := r.ReadAt(p, off+written)
+		if nr > 0 {
+			nw, werr := dst.Write(p[:nr])
+			written += int64(nw)
+			if werr != nil {
+				return written, werr
+			}
+			if nw != nr {
+				return written, io.ErrShortWrite
+			}
+		}
+		if rerr != nil {
+			// ReadAt 读满 p 时也可能返回 io.EOF
+			if rerr == io.EOF && written == n {
+				break
+			}
+			return written, rerr
+		}
+	}
+	return written, nil
+}
+
+func TestCopyTo(t *testing.T) {
+	data := make([]byte, 1<<20)
+	rand.New(rand.NewSource(1)).Read(data)
+	r := bytes.NewReader(data)
+
+	tests := []struct {
+		off, n int64
+	}{
+		{0, 0},
+		{0, 100},
+		{50, 100}, // 与上一个区间重叠
+		{1000, 100 << 10},
+		{1 << 19, 1 << 19}, // 后半部分，正好到末尾
+		{0, int64(len(data))},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		n, err := CopyTo(&buf, r, tt.off, tt.n)
+		if err != nil || n != tt.n {
+			t.Errorf("CopyTo(%d, %d) = (%d, %v)", tt.off, tt.n, n, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), data[tt.off:tt.off+tt.n]) {
+			t.Errorf("CopyTo(%d, %d): content mismatch", tt.off, tt.n)
+		}
+	}
+}
+
+func TestCopyToPastEnd(t *testing.T) {
+	data := []byte("Clear is better than clever")
+	var buf bytes.Buffer
+	n, err := CopyTo(&buf, bytes.NewReader(data), 21, 100)
+	if err != io.EOF || n != 6 || buf.String() != "clever" {
+		t.Errorf("got (%d, %v, %q), want (6, EOF, %q)", n, err, buf.String(), "clever")
+	}
+}
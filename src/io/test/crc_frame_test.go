@@ -0,0 +1,137 @@
+package test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"testing"
+)
+
+var (
+	ErrBadChecksum   = errors.New("crc frame: bad checksum")
+	ErrFrameTooLarge = errors.New("crc frame: frame too large")
+)
+
+// 帧头：4 字节大端序的长度 + 4 字节大端序的 CRC32（IEEE）
+const (
+	crcFrameHeaderLen = 8
+	maxCRCFrameSize   = 16 << 20 // 长度字段损坏时避免分配过大的内存
+)
+
+type CRCFrameWriter struct {
+	w io.Writer
+}
+
+func NewCRCFrameWriter(w io.Writer) *CRCFrameWriter {
+	return &CRCFrameWriter{w: w}
+}
+
+// WriteFrame 把帧头和 payload 拼在一起只调用一次 Write
+func (fw *CRCFrameWriter) WriteFrame(payload []byte) error {
+	if len(payload) > maxCRCFrameSize {
+		return ErrFrameTooLarge
+	}
+	frame := make([]byte, crcFrameHeaderLen+len(payload))
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:8], crc32.ChecksumIEEE(payload))
+	copy(frame[crcFrameHeaderLen:], payload)
+	_, err := fw.w.Write(frame)
+	return err
+}
+
+type CRCFrameReader struct {
+	r      io.Reader
+	header [crcFrameHeaderLen]byte
+}
+
+func NewCRCFrameReader(r io.Reader) *CRCFrameReader {
+	return &CRCFrameReader{r: r}
+}
+
+// ReadFrame 读取下一帧并校验 CRC，返回 payload。
+// 在帧的边界上读到末尾时返回 io.EOF，帧不完整时返回 io.ErrUnexpectedEOF。
+func (fr *CRCFrameReader) ReadFrame() ([]byte, error) {
+	if _, err := io.ReadFull(fr.r, fr.header[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(fr.header[0:4])
+	sum := binary.BigEndian.Uint32(fr.header[4:8])
+	if n > maxCRCFrameSize {
+		return nil, ErrFrameTooLarge
+	}
+
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(fr.r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(payload) != sum {
+		return nil, ErrBadChecksum
+	}
+	return payload, nil
+}
+
+func TestCRCFrameRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCRCFrameWriter(&buf)
+	var frames [][]byte
+	for i := 0; i < 100; i++ {
+		// 包括长度为 0 的帧
+		frame := bytes.Repeat([]byte(fmt.Sprintf("frame %d;", i)), i)
+		frames = append(frames, frame)
+		if err := w.WriteFrame(frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewCRCFrameReader(&buf)
+	for i, want := range frames {
+		got, err := r.ReadFrame()
+		if err != nil {
+			t.Fatalf("frame %d: %v", i, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("frame %d: got %q, want %q", i, got, want)
+		}
+	}
+	if _, err := r.ReadFrame(); err != io.EOF {
+		t.Errorf("got err %v at end, want EOF", err)
+	}
+}
+
+func TestCRCFrameBitFlip(t *testing.T) {
+	var buf bytes.Buffer
+	NewCRCFrameWriter(&buf).WriteFrame([]byte("Clear is better than clever"))
+	NewCRCFrameWriter(&buf).WriteFrame([]byte("Don't panic"))
+
+	// 翻转第一帧 payload 中的一个比特
+	data := buf.Bytes()
+	data[crcFrameHeaderLen+3] ^= 0x10
+
+	r := NewCRCFrameReader(bytes.NewReader(data))
+	if _, err := r.ReadFrame(); err != ErrBadChecksum {
+		t.Errorf("got err %v, want %v", err, ErrBadChecksum)
+	}
+	// 长度字段没有损坏，后面的帧仍然可以正常读取
+	if got, err := r.ReadFrame(); err != nil || string(got) != "Don't panic" {
+		t.Errorf("got (%q, %v)", got, err)
+	}
+}
+
+func TestCRCFrameTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	NewCRCFrameWriter(&buf).WriteFrame([]byte("Errors are values"))
+	data := buf.Bytes()
+
+	for _, n := range []int{3, crcFrameHeaderLen, len(data) - 1} {
+		_, err := NewCRCFrameReader(bytes.NewReader(data[:n])).ReadFrame()
+		if err != io.ErrUnexpectedEOF {
+			t.Errorf("truncated to %d bytes: got err %v, want %v", n, err, io.ErrUnexpectedEOF)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"log"
+	"strings"
+	"testing"
+)
+
+// debugReader 把每次 Read 得到的数据以 hex dump 的形式输出到 logger。
+// hex.Dump 的结果是多行的，每一行都会带上 DEBUG 级别和 label，方便在日志中过滤。
+type debugReader struct {
+	r      io.Reader
+	logger *log.Logger
+	label  string
+}
+
+func NewDebugReader(r io.Reader, logger *log.Logger, label string) io.Reader {
+	return &debugReader{r: r, logger: logger, label: label}
+}
+
+func (d *debugReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if n > 0 {
+		dump := strings.TrimSuffix(hex.Dump(p[:n]), "\n")
+		for _, line := range strings.Split(dump, "\n") {
+			d.logger.Printf("DEBUG [%s] %s", d.label, line)
+		}
+	}
+	return n, err
+}
+
+func TestDebugReader(t *testing.T) {
+	chunks := []string{
+		"Channels orchestrate mutexes serialize",
+		"Cgo is not Go",
+		"Errors are values",
+	}
+
+	var logs bytes.Buffer
+	logger := log.New(&logs, "", 0)
+	r := NewDebugReader(strings.NewReader(strings.Join(chunks, "")), logger, "proverbs")
+
+	for _, c := range chunks {
+		p := make([]byte, len(c))
+		if _, err := io.ReadFull(r, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var want bytes.Buffer
+	for _, c := range chunks {
+		dump := strings.TrimSuffix(hex.Dump([]byte(c)), "\n")
+		for _, line := range strings.Split(dump, "\n") {
+			want.WriteString("DEBUG [proverbs] " + line + "\n")
+		}
+	}
+	if logs.String() != want.String() {
+		t.Errorf("got log:\n%s\nwant:\n%s", logs.String(), want.String())
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(logs.String(), "\n"), "\n") {
+		if !strings.Contains(line, "[proverbs]") {
+			t.Errorf("line %q missing label", line)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// dedupWriter 按行写入，连续重复的行只写一次，
+// 直到出现不同的行时，先补写一行 "[repeated N times]" 再写新行。
+// 未以 '\n' 结尾的数据会先缓存在 line 中，等待后续的 Write 补齐。
+type dedupWriter struct {
+	w     io.Writer
+	line  []byte // 尚未结束的行
+	last  []byte // 上一次真正写出的行（包含 '\n'）
+	count int    // last 连续出现的次数
+}
+
+func NewDedupWriter(w io.Writer) io.Writer {
+	return &dedupWriter{w: w}
+}
+
+func (d *dedupWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			d.line = append(d.line, p...)
+			return n + len(p), nil
+		}
+		d.line = append(d.line, p[:i+1]...)
+		if err := d.writeLine(d.line); err != nil {
+			return n, err
+		}
+		d.line = d.line[:0]
+		n += i + 1
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+func (d *dedupWriter) writeLine(line []byte) error {
+	if d.count > 0 && bytes.Equal(line, d.last) {
+		d.count++
+		return nil
+	}
+	if err := d.flushRepeated(); err != nil {
+		return err
+	}
+	if _, err := d.w.Write(line); err != nil {
+		return err
+	}
+	d.last = append(d.last[:0], line...)
+	d.count = 1
+	return nil
+}
+
+func (d *dedupWriter) flushRepeated() error {
+	if d.count > 1 {
+		if _, err := fmt.Fprintf(d.w, "[repeated %d times]\n", d.count); err != nil {
+			return err
+		}
+	}
+	d.count = 0
+	return nil
+}
+
+// Flush 写出缓存中未结束的行以及尚未输出的重复计数
+func (d *dedupWriter) Flush() error {
+	if len(d.line) > 0 {
+		if err := d.writeLine(d.line); err != nil {
+			return err
+		}
+		d.line = d.line[:0]
+	}
+	return d.flushRepeated()
+}
+
+func TestDedupWriterRepeated(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewDedupWriter(&buf)
+	io.WriteString(w, "ping\n")
+	io.WriteString(w, "ping\n")
+	io.WriteString(w, "ping\n")
+	io.WriteString(w, "pong\n")
+
+	want := "ping\n[repeated 3 times]\npong\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	fmt.Print(buf.String())
+}
+
+func TestDedupWriterNonConsecutive(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewDedupWriter(&buf)
+	io.WriteString(w, "a\n")
+	io.WriteString(w, "b\n")
+	io.WriteString(w, "a\n")
+
+	want := "a\nb\na\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDedupWriterMultiLine(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewDedupWriter(&buf)
+	// 一次 Write 包含多行，且最后一行被拆分到了下一次 Write 中
+	io.WriteString(w, "x\nx\nx\ny\ny")
+	io.WriteString(w, "\nz")
+	if err := w.(*dedupWriter).Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "x\n[repeated 3 times]\ny\n[repeated 2 times]\nz"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+var ErrClosed = errors.New("defer close writer: write after Close")
+
+const deferCloseBufSize = 4096
+
+// DeferCloseWriter 是带缓存的 WriteCloser。
+// Close 时先把缓存中的数据写入 w，只有缓存清空之后才真正调用 w.Close()；
+// 如果写入失败，缓存中还有数据，w.Close() 会推迟到之后某次 Flush 把缓存清空时再调用，
+// 这样 Close 之前写入的数据不会因为底层 Writer 被提前关闭而丢失。
+// Close 之后的 Write 返回 ErrClosed。
+type DeferCloseWriter struct {
+	w       io.WriteCloser
+	buf     []byte
+	closing bool // 已经调用过 Close，等待缓存清空后关闭 w
+	closed  bool // w 已经关闭
+}
+
+func NewDeferCloseWriter(w io.WriteCloser) *DeferCloseWriter {
+	return &DeferCloseWriter{w: w, buf: make([]byte, 0, deferCloseBufSize)}
+}
+
+func (d *DeferCloseWriter) Write(p []byte) (int, error) {
+	if d.closing {
+		return 0, ErrClosed
+	}
+	n := 0
+	for len(p) > 0 {
+		if len(d.buf) == cap(d.buf) {
+			if err := d.Flush(); err != nil {
+				return n, err
+			}
+		}
+		m := copy(d.buf[len(d.buf):cap(d.buf)], p)
+		d.buf = d.buf[:len(d.buf)+m]
+		n += m
+		p = p[m:]
+	}
+	return n, nil
+}
+
+// Flush 把缓存写入 w，在 Close 之后清空缓存时会关闭 w
+func (d *DeferCloseWriter) Flush() error {
+	for len(d.buf) > 0 {
+		n, err := d.w.Write(d.buf)
+		d.buf = d.buf[:copy(d.buf, d.buf[n:])]
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return io.ErrShortWrite
+		}
+	}
+	if d.closing && !d.closed {
+		d.closed = true
+		return d.w.Close()
+	}
+	return nil
+}
+
+func (d *DeferCloseWriter) Close() error {
+	if d.closing {
+		return nil
+	}
+	d.closing = true
+	return d.Flush()
+}
+
+// flakyWriteCloser 的前 fails 次 Write 失败，关闭之后 Write 返回 ErrClosed
+type flakyWriteCloser struct {
+	bytes.Buffer
+	fails  int
+	closed bool
+}
+
+var errTemporary = errors.New("temporary failure")
+
+func (f *flakyWriteCloser) Write(p []byte) (int, error) {
+	if f.closed {
+		return 0, ErrClosed
+	}
+	if f.fails > 0 {
+		f.fails--
+		return 0, errTemporary
+	}
+	return f.Buffer.Write(p)
+}
+
+func (f *flakyWriteCloser) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestDeferCloseWriter(t *testing.T) {
+	dst := &flakyWriteCloser{}
+	w := NewDeferCloseWriter(dst)
+	io.WriteString(w, "Errors are values")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if dst.String() != "Errors are values" || !dst.closed {
+		t.Errorf("got (%q, closed=%v), want data flushed and closed", dst.String(), dst.closed)
+	}
+
+	if _, err := io.WriteString(w, "more"); err != ErrClosed {
+		t.Errorf("got err %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestDeferCloseWriterDelayedClose(t *testing.T) {
+	dst := &flakyWriteCloser{fails: 1}
+	w := NewDeferCloseWriter(dst)
+	io.WriteString(w, "Don't panic")
+
+	// 第一次写入失败，缓存中还有数据，推迟关闭
+	if err := w.Close(); err != errTemporary {
+		t.Fatalf("got err %v, want %v", err, errTemporary)
+	}
+	if dst.closed {
+		t.Fatal("underlying writer closed with unflushed data")
+	}
+	if _, err := io.WriteString(w, "more"); err != ErrClosed {
+		t.Errorf("got err %v, want %v", err, ErrClosed)
+	}
+
+	// 再次 Flush 成功后才真正关闭
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if dst.String() != "Don't panic" || !dst.closed {
+		t.Errorf("got (%q, closed=%v), want data flushed and closed", dst.String(), dst.closed)
+	}
+}
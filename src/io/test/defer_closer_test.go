@@ -0,0 +1,91 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"testing"
+)
+
+// DeferCloser 收集需要关闭的资源，最后通过一次 Close 统一关闭。
+// 和 defer 一样按照后进先出（LIFO）的顺序关闭，所有错误通过 errors.Join 合并返回。
+type DeferCloser struct {
+	mu      sync.Mutex
+	closers []io.Closer
+}
+
+func NewDeferCloser() *DeferCloser {
+	return &DeferCloser{}
+}
+
+func (d *DeferCloser) Add(c io.Closer) {
+	d.mu.Lock()
+	d.closers = append(d.closers, c)
+	d.mu.Unlock()
+}
+
+// Close 按注册的逆序关闭所有资源，关闭后清空列表，可以继续 Add 新的资源
+func (d *DeferCloser) Close() error {
+	d.mu.Lock()
+	closers := d.closers
+	d.closers = nil
+	d.mu.Unlock()
+
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CloseAll 等同于 Close
+func (d *DeferCloser) CloseAll() error {
+	return d.Close()
+}
+
+func TestDeferCloserLIFO(t *testing.T) {
+	var order []int
+	d := NewDeferCloser()
+	for i := 1; i <= 5; i++ {
+		i := i
+		d.Add(closerFunc(func() error {
+			order = append(order, i)
+			return nil
+		}))
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []int{5, 4, 3, 2, 1}
+	if len(order) != len(want) {
+		t.Fatalf("got %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("got %v, want %v", order, want)
+		}
+	}
+}
+
+func TestDeferCloserErrors(t *testing.T) {
+	errClose := errors.New("close failed")
+	var called int
+	d := NewDeferCloser()
+	d.Add(closerFunc(func() error { called++; return nil }))
+	d.Add(closerFunc(func() error { called++; return errClose }))
+
+	if err := d.CloseAll(); !errors.Is(err, errClose) {
+		t.Errorf("got err %v, want %v", err, errClose)
+	}
+	if called != 2 {
+		t.Errorf("got %d closers called, want 2", called)
+	}
+	// 已经关闭过的资源不会再被关闭
+	d.Close()
+	if called != 2 {
+		t.Errorf("got %d closers called after second Close, want 2", called)
+	}
+}
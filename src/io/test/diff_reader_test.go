@@ -0,0 +1,228 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+type diffOp struct {
+	kind byte // ' '、'-' 或 '+'
+	text string
+}
+
+// NewDiffReader 读完 a 和 b，按行比较，返回统一格式（unified diff）的差异，
+// 每个差异块前后保留 context 行上下文（负数按 0 处理）。两者相同时返回的 Reader 没有数据。
+// 与 GNU diff 一样，最后一行缺少换行符时与带换行符的同一行视为不同，输出时加上 "\ No newline at end of file"。
+// 使用最长公共子序列计算差异，时间和空间复杂度都是 O(n*m)，只适合比较较小的文本。
+func NewDiffReader(a, b io.Reader, context int) io.Reader {
+	if context < 0 {
+		context = 0
+	}
+	ab, err := io.ReadAll(a)
+	if err != nil {
+		return errorReader{err}
+	}
+	bb, err := io.ReadAll(b)
+	if err != nil {
+		return errorReader{err}
+	}
+	ops := diffLines(splitLines(string(ab)), splitLines(string(bb)))
+	return bytes.NewReader(unifiedDiff(ops, context))
+}
+
+// splitLines 按行切分，每行保留结尾的换行符，最后一行可能没有换行符
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines 通过最长公共子序列得到把 a 变成 b 的编辑序列
+func diffLines(a, b []string) []diffOp {
+	// lcs[i][j] 是 a[i:] 和 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i, j = i+1, j+1
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	return ops
+}
+
+// unifiedDiff 把相距不超过 2*context 的修改合并到同一个差异块中
+func unifiedDiff(ops []diffOp, context int) []byte {
+	var changes []int
+	for i, op := range ops {
+		if op.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("--- a\n+++ b\n")
+	for k := 0; k < len(changes); {
+		first := changes[k]
+		last := first
+		for k++; k < len(changes) && changes[k]-last-1 <= 2*context; k++ {
+			last = changes[k]
+		}
+		start := max(0, first-context)
+		end := min(len(ops), last+context+1)
+
+		// 统计差异块之前两边各有多少行，以及块内各有多少行
+		aPos, bPos := 0, 0
+		for _, op := range ops[:start] {
+			if op.kind != '+' {
+				aPos++
+			}
+			if op.kind != '-' {
+				bPos++
+			}
+		}
+		aCount, bCount := 0, 0
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				aCount++
+			}
+			if op.kind != '-' {
+				bCount++
+			}
+		}
+
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n", hunkRange(aPos, aCount), hunkRange(bPos, bCount))
+		for _, op := range ops[start:end] {
+			buf.WriteByte(op.kind)
+			buf.WriteString(op.text)
+			if !strings.HasSuffix(op.text, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+// hunkRange 按 GNU diff 的格式输出范围：行号从 1 开始，只有一行时省略行数，
+// 没有行时行号是前一行的行号
+func hunkRange(pos, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", pos)
+	case 1:
+		return fmt.Sprintf("%d", pos+1)
+	}
+	return fmt.Sprintf("%d,%d", pos+1, count)
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func TestDiffReader(t *testing.T) {
+	a := "a\nb\nc\nd\ne\nf\ng\n"
+	b := "a\nb\nC\nd\ne\nf\ng\nh\n"
+	want := "--- a\n+++ b\n" +
+		"@@ -2,3 +2,3 @@\n b\n-c\n+C\n d\n" +
+		"@@ -7 +7,2 @@\n g\n+h\n"
+
+	got, err := io.ReadAll(NewDiffReader(strings.NewReader(a), strings.NewReader(b), 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	fmt.Print(string(got))
+}
+
+func TestDiffReaderMergedHunk(t *testing.T) {
+	a := "Clear is better than clever\nCgo is not Go\nErrors are values\nDon't panic\n"
+	b := "Clear is better than clever\nErrors are values\nDon't just check errors, handle them gracefully\nDon't panic\n"
+	// 两处修改之间只隔一行，上下文为 3 时合并成一个差异块
+	want := "--- a\n+++ b\n" +
+		"@@ -1,4 +1,4 @@\n" +
+		" Clear is better than clever\n" +
+		"-Cgo is not Go\n" +
+		" Errors are values\n" +
+		"+Don't just check errors, handle them gracefully\n" +
+		" Don't panic\n"
+
+	got, _ := io.ReadAll(NewDiffReader(strings.NewReader(a), strings.NewReader(b), 3))
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiffReaderIdentical(t *testing.T) {
+	s := "Errors are values\n"
+	got, err := io.ReadAll(NewDiffReader(strings.NewReader(s), strings.NewReader(s), 3))
+	if err != nil || len(got) != 0 {
+		t.Errorf("got (%q, %v), want empty", got, err)
+	}
+
+	// 一边为空时，范围的行号为 0
+	got, _ = io.ReadAll(NewDiffReader(strings.NewReader(""), strings.NewReader(s), 3))
+	if want := "--- a\n+++ b\n@@ -0,0 +1 @@\n+Errors are values\n"; string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDiffReaderNoNewline(t *testing.T) {
+	got, _ := io.ReadAll(NewDiffReader(strings.NewReader("a\nb"), strings.NewReader("a\nb\n"), 3))
+	want := "--- a\n+++ b\n" +
+		"@@ -1,2 +1,2 @@\n" +
+		" a\n" +
+		"-b\n\\ No newline at end of file\n" +
+		"+b\n"
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiffReaderNegativeContext(t *testing.T) {
+	// 负数的上下文按 0 处理，不能 panic
+	got, err := io.ReadAll(NewDiffReader(strings.NewReader("a\nb\nc\n"), strings.NewReader("a\nB\nc\n"), -1))
+	if want := "--- a\n+++ b\n@@ -2 +2 @@\n-b\n+B\n"; err != nil || string(got) != want {
+		t.Errorf("got (%q, %v), want %q", got, err, want)
+	}
+}
@@ -0,0 +1,61 @@
+package test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// echo 逐行读取 r，把每一行原样写回 w。
+// r 返回 io.EOF（对端关闭了写入端）时关闭 w 并返回，对端读到 io.EOF 就知道服务已经结束。
+func echo(r io.Reader, w io.WriteCloser) error {
+	defer w.Close()
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if _, err := fmt.Fprintln(w, s.Text()); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+// 两个 io.Pipe 组成双向的连接：客户端写 req 由服务端读取，服务端写 resp 由客户端读取。
+// io.Pipe 没有缓存，每次 Write 都会阻塞到对端读走数据为止，所以客户端要发一行读一行，
+// 不能先把所有行都写完再读（服务端会阻塞在写 resp 上，客户端阻塞在写 req 上，形成死锁）。
+//
+// 关闭的顺序：
+//  1. 客户端关闭 reqW，服务端的 Scanner 读到 io.EOF，Scan 返回 false
+//  2. 服务端 echo 返回前关闭 respW
+//  3. 客户端从 respR 读到 io.EOF，确认服务端的 goroutine 已经结束
+func TestEchoServer(t *testing.T) {
+	reqR, reqW := io.Pipe()
+	respR, respW := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- echo(reqR, respW)
+	}()
+
+	resp := bufio.NewScanner(respR)
+	for i := 1; i <= 5; i++ {
+		line := fmt.Sprintf("line %d", i)
+		if _, err := fmt.Fprintln(reqW, line); err != nil {
+			t.Fatal(err)
+		}
+		if !resp.Scan() {
+			t.Fatalf("no echo for %q: %v", line, resp.Err())
+		}
+		if resp.Text() != line {
+			t.Errorf("got %q, want %q", resp.Text(), line)
+		}
+	}
+
+	reqW.Close()
+	if resp.Scan() {
+		t.Errorf("got unexpected line %q after close", resp.Text())
+	}
+	if err := <-done; err != nil {
+		t.Errorf("echo returned %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"testing/iotest"
+)
+
+type fanInChunk struct {
+	data []byte
+	err  error
+}
+
+// fanInReader 把多个 Reader 合并成一个流。
+// 每个底层 Reader 在自己的 goroutine 中读取，读到的数据块发送到各自的 channel，
+// Read 按轮询（round-robin）的顺序从这些 channel 中取数据块，都没有数据时阻塞等待。
+// 同一个来源内部的数据顺序保持不变，不同来源之间的顺序不做保证。
+type fanInReader struct {
+	chs  []chan fanInChunk
+	next int    // 下一次轮询开始的位置
+	buf  []byte // 上一个数据块中还没被读走的部分
+}
+
+func NewFanInReader(readers ...io.Reader) io.Reader {
+	f := &fanInReader{}
+	for _, r := range readers {
+		ch := make(chan fanInChunk, 1)
+		f.chs = append(f.chs, ch)
+		go fanInPump(r, ch)
+	}
+	return f
+}
+
+func fanInPump(r io.Reader, ch chan<- fanInChunk) {
+	defer close(ch)
+	for {
+		buf := make([]byte, 512)
+		n, err := r.Read(buf)
+		if n > 0 {
+			ch <- fanInChunk{data: buf[:n]}
+		}
+		if err != nil {
+			if err != io.EOF {
+				ch <- fanInChunk{err: err}
+			}
+			return
+		}
+	}
+}
+
+func (f *fanInReader) Read(p []byte) (int, error) {
+	for len(f.buf) == 0 {
+		if len(f.chs) == 0 {
+			return 0, io.EOF
+		}
+		c, ok, i := f.poll()
+		if !ok {
+			// 该来源已经读完，从轮询列表中移除
+			f.chs = append(f.chs[:i], f.chs[i+1:]...)
+			if f.next > i {
+				f.next--
+			}
+			continue
+		}
+		if c.err != nil {
+			return 0, c.err
+		}
+		f.buf = c.data
+	}
+
+	n := copy(p, f.buf)
+	f.buf = f.buf[n:]
+	return n, nil
+}
+
+// poll 从 next 开始依次尝试非阻塞地接收，都没有数据时再阻塞在全部 channel 上
+func (f *fanInReader) poll() (fanInChunk, bool, int) {
+	for k := 0; k < len(f.chs); k++ {
+		i := (f.next + k) % len(f.chs)
+		select {
+		case c, ok := <-f.chs[i]:
+			f.next = (i + 1) % len(f.chs)
+			return c, ok, i
+		default:
+		}
+	}
+
+	cases := make([]reflect.SelectCase, len(f.chs))
+	for i, ch := range f.chs {
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch)}
+	}
+	i, v, ok := reflect.Select(cases)
+	f.next = (i + 1) % len(f.chs)
+	if !ok {
+		return fanInChunk{}, false, i
+	}
+	return v.Interface().(fanInChunk), true, i
+}
+
+func TestFanInReader(t *testing.T) {
+	const sources, size = 5, 200
+
+	// 第 i 个来源的每个字节都是 'a'+i，方便按来源统计
+	readers := make([]io.Reader, sources)
+	for i := range readers {
+		data := bytes.Repeat([]byte{byte('a' + i)}, size)
+		readers[i] = iotest.HalfReader(bytes.NewReader(data))
+	}
+
+	b, err := io.ReadAll(NewFanInReader(readers...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != sources*size {
+		t.Fatalf("got %d bytes, want %d", len(b), sources*size)
+	}
+
+	count := make(map[byte]int)
+	for _, c := range b {
+		count[c]++
+	}
+	for i := 0; i < sources; i++ {
+		if n := count[byte('a'+i)]; n != size {
+			t.Errorf("source %d: got %d bytes, want %d", i, n, size)
+		}
+	}
+}
+
+func TestFanInReaderError(t *testing.T) {
+	r := NewFanInReader(bytes.NewReader([]byte("ok")), iotest.ErrReader(io.ErrClosedPipe))
+	if _, err := io.ReadAll(r); err != io.ErrClosedPipe {
+		t.Errorf("got err %v, want %v", err, io.ErrClosedPipe)
+	}
+}
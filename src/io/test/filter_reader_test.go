@@ -0,0 +1,92 @@
+package test
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// filterReader 去掉 r 中 reject 返回 true 的字节，留下的字节在 p 中原地前移，不需要额外的缓存。
+// 返回的 n 是过滤之后的字节数，可能比底层 Read 返回的少；
+// 一次读到的字节全部被去掉时继续读取，不返回 0, nil。
+type filterReader struct {
+	r      io.Reader
+	reject func(byte) bool
+}
+
+func NewFilterReader(r io.Reader, reject func(byte) bool) io.Reader {
+	return &filterReader{r: r, reject: reject}
+}
+
+func (f *filterReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for {
+		n, err := f.r.Read(p)
+		kept := 0
+		for _, c := range p[:n] {
+			if !f.reject(c) {
+				p[kept] = c
+				kept++
+			}
+		}
+		if kept > 0 || err != nil {
+			return kept, err
+		}
+	}
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func TestFilterReader(t *testing.T) {
+	const s = "a1b22c333 Go 1.20"
+	got, err := io.ReadAll(NewFilterReader(strings.NewReader(s), isDigit))
+	if err != nil || string(got) != "abc Go ." {
+		t.Errorf("got (%q, %v), want %q", got, err, "abc Go .")
+	}
+	if len(got) >= len(s) {
+		t.Errorf("got %d bytes, want fewer than %d", len(got), len(s))
+	}
+}
+
+func TestFilterReaderCount(t *testing.T) {
+	// 每次 Read 只看 p[:n]，p 中 n 之后的内容是过滤前留下的，不能使用
+	r := NewFilterReader(iotest.HalfReader(strings.NewReader("12ab34cd56")), isDigit)
+	p := make([]byte, 4)
+	var got []byte
+	total := 0
+	for {
+		n, err := r.Read(p)
+		if n > len(p) {
+			t.Fatalf("got n = %d > len(p) = %d", n, len(p))
+		}
+		for _, c := range p[:n] {
+			if isDigit(c) {
+				t.Errorf("got digit %q in p[:%d] = %q", c, n, p[:n])
+			}
+		}
+		got = append(got, p[:n]...)
+		total += n
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if string(got) != "abcd" || total != 4 {
+		t.Errorf("got (%q, %d bytes), want (%q, 4 bytes)", got, total, "abcd")
+	}
+}
+
+func TestFilterReaderAllRejected(t *testing.T) {
+	// 全部被过滤时不返回 0, nil，直接返回底层的 EOF
+	r := NewFilterReader(iotest.OneByteReader(strings.NewReader("2023")), isDigit)
+	if n, err := r.Read(make([]byte, 8)); n != 0 || err != io.EOF {
+		t.Errorf("got (%d, %v), want (0, EOF)", n, err)
+	}
+}
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"hash"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+// FingerprintReader 在读取数据的同时计算多个哈希值，只需要读一遍数据。
+// 与 io.TeeReader(r, io.MultiWriter(hashes...)) 效果相同。
+type FingerprintReader struct {
+	r      io.Reader
+	hashes []hash.Hash
+}
+
+func NewFingerprintReader(r io.Reader, hashes ...hash.Hash) *FingerprintReader {
+	return &FingerprintReader{r: r, hashes: hashes}
+}
+
+func (f *FingerprintReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	for _, h := range f.hashes {
+		// hash.Hash 的 Write 不会返回错误
+		h.Write(p[:n])
+	}
+	return n, err
+}
+
+// Sums 按创建时的顺序返回各个哈希值，应该在读完数据之后调用
+func (f *FingerprintReader) Sums() [][]byte {
+	sums := make([][]byte, len(f.hashes))
+	for i, h := range f.hashes {
+		sums[i] = h.Sum(nil)
+	}
+	return sums
+}
+
+func TestFingerprintReader(t *testing.T) {
+	data := make([]byte, 1<<20)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	r := NewFingerprintReader(bytes.NewReader(data), md5.New(), sha1.New(), sha256.New())
+	n, err := io.Copy(io.Discard, r)
+	if err != nil || n != int64(len(data)) {
+		t.Fatalf("got (%d, %v)", n, err)
+	}
+
+	md5Sum := md5.Sum(data)
+	sha1Sum := sha1.Sum(data)
+	sha256Sum := sha256.Sum256(data)
+	want := [][]byte{md5Sum[:], sha1Sum[:], sha256Sum[:]}
+
+	got := r.Sums()
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) {
+			t.Errorf("hash %d: got %x, want %x", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// Flusher 是带缓存的 Writer 共有的方法，bufio.Writer、gzip.Writer 等都实现了它，
+// 但标准库中没有对应的接口（http.Flusher 的 Flush 没有返回值）。
+type Flusher interface {
+	Flush() error
+}
+
+// Flush 在 w 实现了 Flusher 时调用它的 Flush，否则什么也不做并返回 nil
+func Flush(w io.Writer) error {
+	if f, ok := w.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// failWriter 的每次 Write 都返回 err
+type failWriter struct {
+	err error
+}
+
+func (f failWriter) Write(p []byte) (int, error) {
+	return 0, f.err
+}
+
+func TestFlush(t *testing.T) {
+	var buf bytes.Buffer
+	bw := bufio.NewWriter(&buf)
+	io.WriteString(bw, "Cgo is not Go")
+	if buf.Len() != 0 {
+		t.Fatal("bufio.Writer wrote before Flush")
+	}
+	if err := Flush(bw); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "Cgo is not Go" {
+		t.Errorf("got %q after Flush", buf.String())
+	}
+
+	// bytes.Buffer 没有 Flush 方法
+	if err := Flush(&buf); err != nil {
+		t.Errorf("got err %v for non-flusher, want nil", err)
+	}
+
+	// dedupWriter 也实现了 Flusher
+	buf.Reset()
+	dw := NewDedupWriter(&buf)
+	io.WriteString(dw, "a\na\n")
+	Flush(dw)
+	if want := "a\n[repeated 2 times]\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFlushError(t *testing.T) {
+	errWrite := errors.New("write failed")
+	bw := bufio.NewWriter(failWriter{errWrite})
+	io.WriteString(bw, "Don't panic")
+	if err := Flush(bw); err != errWrite {
+		t.Errorf("got err %v, want %v", err, errWrite)
+	}
+}
@@ -0,0 +1,134 @@
+package test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+var ErrFrameSplitterTooLarge = errors.New("frame splitter: frame too large")
+
+const defaultMaxFrameSize = 16 << 20 // 与 maxCRCFrameSize 相同
+
+// FrameSplitter 从字节流中读取 <长度><数据> 格式的帧，长度字段占 lengthField 个字节（1 到 8），
+// 按大端序或小端序解释为无符号整数。
+// 长度来自数据流本身，分配内存之前先与 MaxFrameSize 比较，
+// 损坏或恶意的长度字段不会导致 panic 或分配过大的内存。
+type FrameSplitter struct {
+	// MaxFrameSize 是允许的最大帧长度，默认为 16MB，需要更大的帧时在第一次 ReadFrame 之前修改
+	MaxFrameSize int
+
+	r         io.Reader
+	bigEndian bool
+	header    []byte
+}
+
+func NewFrameSplitter(r io.Reader, lengthField int, bigEndian bool) *FrameSplitter {
+	if lengthField < 1 || lengthField > 8 {
+		panic("FrameSplitter: lengthField must be between 1 and 8")
+	}
+	return &FrameSplitter{MaxFrameSize: defaultMaxFrameSize, r: r, bigEndian: bigEndian, header: make([]byte, lengthField)}
+}
+
+// ReadFrame 返回下一帧的数据，长度为 0 的帧返回空切片而不是 nil。
+// 在帧的边界上读到末尾时返回 io.EOF，帧不完整时返回 io.ErrUnexpectedEOF，
+// 长度超过 MaxFrameSize 时返回 ErrFrameSplitterTooLarge，这时流已经无法继续解析。
+func (f *FrameSplitter) ReadFrame() ([]byte, error) {
+	if _, err := io.ReadFull(f.r, f.header); err != nil {
+		return nil, err
+	}
+	var n uint64
+	for i := range f.header {
+		b := f.header[i]
+		if !f.bigEndian {
+			b = f.header[len(f.header)-1-i]
+		}
+		n = n<<8 | uint64(b)
+	}
+
+	if f.MaxFrameSize < 0 || n > uint64(f.MaxFrameSize) {
+		return nil, ErrFrameSplitterTooLarge
+	}
+	frame := make([]byte, n)
+	if _, err := io.ReadFull(f.r, frame); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return frame, nil
+}
+
+func TestFrameSplitter(t *testing.T) {
+	lengths := []int{0, 1, 255, 256, 65535}
+
+	for _, bigEndian := range []bool{true, false} {
+		var order binary.ByteOrder = binary.LittleEndian
+		if bigEndian {
+			order = binary.BigEndian
+		}
+		// 帧首尾相接，长度字段 4 个字节
+		var buf bytes.Buffer
+		for i, n := range lengths {
+			var header [4]byte
+			order.PutUint32(header[:], uint32(n))
+			buf.Write(header[:])
+			buf.Write(bytes.Repeat([]byte{byte('a' + i)}, n))
+		}
+
+		s := NewFrameSplitter(&buf, 4, bigEndian)
+		for i, n := range lengths {
+			frame, err := s.ReadFrame()
+			if err != nil {
+				t.Fatalf("bigEndian=%v frame %d: %v", bigEndian, i, err)
+			}
+			if frame == nil {
+				t.Errorf("bigEndian=%v frame %d: got nil, want non-nil slice", bigEndian, i)
+			}
+			if !bytes.Equal(frame, bytes.Repeat([]byte{byte('a' + i)}, n)) {
+				t.Errorf("bigEndian=%v frame %d: got %d bytes, want %d", bigEndian, i, len(frame), n)
+			}
+		}
+		if _, err := s.ReadFrame(); err != io.EOF {
+			t.Errorf("bigEndian=%v: got err %v at end, want EOF", bigEndian, err)
+		}
+	}
+}
+
+func TestFrameSplitterFieldSize(t *testing.T) {
+	// 2 字节的小端序长度字段：0x0100 = 256
+	data := append([]byte{0x00, 0x01}, bytes.Repeat([]byte("x"), 256)...)
+	frame, err := NewFrameSplitter(bytes.NewReader(data), 2, false).ReadFrame()
+	if err != nil || len(frame) != 256 {
+		t.Errorf("got (%d bytes, %v), want 256 bytes", len(frame), err)
+	}
+
+	// 数据不完整
+	_, err = NewFrameSplitter(bytes.NewReader([]byte{3, 'a', 'b'}), 1, true).ReadFrame()
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("got err %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestFrameSplitterTooLarge(t *testing.T) {
+	// 8 字节的长度字段全是 0xff，直接 make 会 panic；默认的上限就能拒绝它
+	hostile := bytes.Repeat([]byte{0xff}, 8)
+	if _, err := NewFrameSplitter(bytes.NewReader(hostile), 8, true).ReadFrame(); err != ErrFrameSplitterTooLarge {
+		t.Errorf("got err %v, want %v", err, ErrFrameSplitterTooLarge)
+	}
+
+	// 正好等于上限的帧可以读取，多一个字节就不行
+	data := append([]byte{4}, "Gopher"...)
+	s := NewFrameSplitter(bytes.NewReader(data), 1, true)
+	s.MaxFrameSize = 4
+	if frame, err := s.ReadFrame(); err != nil || string(frame) != "Goph" {
+		t.Errorf("got (%q, %v), want %q", frame, err, "Goph")
+	}
+	s = NewFrameSplitter(bytes.NewReader(data), 1, true)
+	s.MaxFrameSize = 3
+	if _, err := s.ReadFrame(); err != ErrFrameSplitterTooLarge {
+		t.Errorf("got err %v, want %v", err, ErrFrameSplitterTooLarge)
+	}
+}
@@ -0,0 +1,79 @@
+// Package golden 提供基于 golden 文件的快照测试辅助函数。
+//
+// 期望输出保存在测试目录下的 testdata/<name>.golden 中，
+// 输出有意变化时使用 -update 参数重新生成：
+//
+//	go test -update
+package golden
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// Path 返回 name 对应的 golden 文件路径
+func Path(name string) string {
+	return filepath.Join("testdata", name+".golden")
+}
+
+// CheckGolden 把 got 与 golden 文件逐字节比较，不一致时输出逐行的差异。
+// golden 文件不存在或者指定了 -update 时，写入 got 并通过。
+func CheckGolden(t testing.TB, name string, got []byte) {
+	t.Helper()
+	path := Path(name)
+
+	want, err := os.ReadFile(path)
+	if *update || errors.Is(err, fs.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("wrote golden file %s", path)
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (-want +got):\n%s", path, diff(string(want), string(got)))
+	}
+}
+
+// diff 逐行比较 want 和 got，只输出不同的行
+func diff(want, got string) string {
+	wl := strings.Split(want, "\n")
+	gl := strings.Split(got, "\n")
+
+	var b strings.Builder
+	for i := 0; i < len(wl) || i < len(gl); i++ {
+		var w, g string
+		if i < len(wl) {
+			w = wl[i]
+		}
+		if i < len(gl) {
+			g = gl[i]
+		}
+		if w == g {
+			continue
+		}
+		if i < len(wl) {
+			fmt.Fprintf(&b, "%d: - %q\n", i+1, w)
+		}
+		if i < len(gl) {
+			fmt.Fprintf(&b, "%d: + %q\n", i+1, g)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package golden
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+// hex.Dumper 返回的 WriteCloser 以 hexdump -C 的格式输出写入的数据
+func TestHexDumpGolden(t *testing.T) {
+	var buf bytes.Buffer
+	w := hex.Dumper(&buf)
+	w.Write([]byte("Channels orchestrate mutexes serialize\n"))
+	w.Write([]byte("Cgo is not Go\n"))
+	w.Close()
+
+	CheckGolden(t, "hexdump", buf.Bytes())
+}
+
+// json.Encoder 每次 Encode 输出一个 JSON 值并以换行结尾，也就是 NDJSON 格式
+func TestNDJSONGolden(t *testing.T) {
+	type proverb struct {
+		ID   int    `json:"id"`
+		Text string `json:"text"`
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i, p := range []string{"Errors are values", "Don't panic"} {
+		if err := enc.Encode(proverb{ID: i + 1, Text: p}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	CheckGolden(t, "ndjson", buf.Bytes())
+}
+
+// fakeTB 记录 Errorf 的调用而不是让测试失败
+type fakeTB struct {
+	testing.TB
+	failed string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.failed = format
+}
+
+func TestCheckGoldenMismatch(t *testing.T) {
+	name := "mismatch-" + t.Name()
+	path := Path(name)
+	if err := os.WriteFile(path, []byte("a\nb\nc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(path)
+
+	tb := &fakeTB{TB: t}
+	CheckGolden(tb, name, []byte("a\nB\nc\n"))
+	if tb.failed == "" {
+		t.Fatal("CheckGolden passed on mismatched output")
+	}
+
+	d := diff("a\nb\nc\n", "a\nB\nc\n")
+	if !strings.Contains(d, `2: - "b"`) || !strings.Contains(d, `2: + "B"`) {
+		t.Errorf("unexpected diff:\n%s", d)
+	}
+}
+
+func TestCheckGoldenCreate(t *testing.T) {
+	name := "create-" + t.Name()
+	path := Path(name)
+	os.Remove(path)
+	defer os.Remove(path)
+
+	CheckGolden(t, name, []byte("new"))
+	b, err := os.ReadFile(path)
+	if err != nil || string(b) != "new" {
+		t.Errorf("got (%q, %v), want golden file to be created", b, err)
+	}
+}
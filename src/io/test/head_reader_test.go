@@ -0,0 +1,89 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// headReader 只读取前 n 个字节，之后总是返回 io.EOF。
+// 行为和 io.LimitReader 一样，单独定义一个类型是为了让调用处的意图更清楚。
+type headReader struct {
+	r io.Reader
+	n int64 // 剩余可以读取的字节数
+}
+
+func NewHeadReader(r io.Reader, n int64) io.Reader {
+	return &headReader{r: r, n: n}
+}
+
+func (h *headReader) Read(p []byte) (int, error) {
+	if h.n <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > h.n {
+		p = p[:h.n]
+	}
+	n, err := h.r.Read(p)
+	h.n -= int64(n)
+	return n, err
+}
+
+// errorReader 的每次 Read 都返回 err
+type errorReader struct {
+	err error
+}
+
+func (e errorReader) Read(p []byte) (int, error) {
+	return 0, e.err
+}
+
+// TailReader 定位到倒数第 n 个字节（数据不足 n 个字节时从头开始），返回从那里开始读取的 Reader。
+// Seek 失败时返回的 Reader 在 Read 时返回该错误。
+func TailReader(r io.ReadSeeker, n int64) io.Reader {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return errorReader{err}
+	}
+	off := size - n
+	if off < 0 {
+		off = 0
+	}
+	if _, err := r.Seek(off, io.SeekStart); err != nil {
+		return errorReader{err}
+	}
+	return r
+}
+
+func TestHeadReader(t *testing.T) {
+	r := NewHeadReader(strings.NewReader("Clear is better than clever"), 5)
+	b, err := io.ReadAll(r)
+	if err != nil || string(b) != "Clear" {
+		t.Errorf("got (%q, %v), want %q", b, err, "Clear")
+	}
+	if n, err := r.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Errorf("got (%d, %v), want (0, EOF)", n, err)
+	}
+}
+
+func TestTailReader(t *testing.T) {
+	data := make([]byte, 1024)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	b, err := io.ReadAll(TailReader(bytes.NewReader(data), 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, data[len(data)-100:]) {
+		t.Errorf("got %d bytes starting with %d, want last 100 bytes", len(b), b[0])
+	}
+
+	// n 超过数据长度时返回全部数据
+	b, _ = io.ReadAll(TailReader(bytes.NewReader(data[:10]), 100))
+	if !bytes.Equal(b, data[:10]) {
+		t.Errorf("got %v, want %v", b, data[:10])
+	}
+}
@@ -0,0 +1,137 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var (
+	ErrOddHex     = errors.New("hex reader: odd number of hex characters")
+	ErrInvalidHex = errors.New("hex reader: invalid hex character")
+)
+
+// hexReader 是 hexWriter 的逆操作：每读到两个十六进制字符解码成一个字节，大小写都可以。
+// 底层的 Read 可能在一对字符的中间返回，多出的一个字符留到下一次与后面的字符凑成一对。
+// 遇到非十六进制字符时，返回它之前解码出的字节和 ErrInvalidHex；
+// 流结束时还剩一个字符则返回 ErrOddHex。两种错误之后的 Read 都返回同样的错误。
+type hexReader struct {
+	r    io.Reader
+	buf  [2 * hexChunk]byte
+	nbuf int // buf 中还没有解码的字符数
+	err  error
+}
+
+func NewHexReader(r io.Reader) io.Reader {
+	return &hexReader{r: r}
+}
+
+func (h *hexReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for h.nbuf < 2 {
+		if h.err != nil {
+			if h.err == io.EOF && h.nbuf == 1 {
+				if _, ok := fromHexChar(h.buf[0]); !ok {
+					h.err = ErrInvalidHex
+				} else {
+					h.err = ErrOddHex
+				}
+				h.nbuf = 0
+			}
+			return 0, h.err
+		}
+		// 最多读取填满 p 所需的字符数
+		end := h.nbuf + 2*len(p)
+		if end > len(h.buf) {
+			end = len(h.buf)
+		}
+		n, err := h.r.Read(h.buf[h.nbuf:end])
+		h.nbuf += n
+		h.err = err
+	}
+
+	n := h.nbuf / 2
+	if n > len(p) {
+		n = len(p)
+	}
+	for i := 0; i < n; i++ {
+		hi, ok1 := fromHexChar(h.buf[2*i])
+		lo, ok2 := fromHexChar(h.buf[2*i+1])
+		if !ok1 || !ok2 {
+			h.nbuf = 0
+			h.err = ErrInvalidHex
+			return i, h.err
+		}
+		p[i] = hi<<4 | lo
+	}
+	h.nbuf = copy(h.buf[:], h.buf[2*n:h.nbuf])
+	return n, nil
+}
+
+func fromHexChar(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+func TestHexReaderRoundTrip(t *testing.T) {
+	for _, data := range [][]byte{
+		{},
+		{0x00, 0xff, 0xab},
+		[]byte("Clear is better than clever"),
+		bytes.Repeat([]byte("Don't panic."), 500), // 超过一次解码的缓存大小
+	} {
+		var buf bytes.Buffer
+		w := NewHexWriter(&buf)
+		w.Write(data)
+		w.Close()
+
+		// OneByteReader 让每一对字符都被拆到两次 Read 中
+		got, err := io.ReadAll(NewHexReader(iotest.OneByteReader(&buf)))
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("got (%d bytes, %v), want %d bytes", len(got), err, len(data))
+		}
+	}
+}
+
+func TestHexReaderUpperCase(t *testing.T) {
+	got, err := io.ReadAll(NewHexReader(strings.NewReader("00FFaB")))
+	if err != nil || !bytes.Equal(got, []byte{0x00, 0xff, 0xab}) {
+		t.Errorf("got (% x, %v)", got, err)
+	}
+}
+
+func TestHexReaderErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []byte
+		err  error
+	}{
+		{"00f fab", []byte{0x00}, ErrInvalidHex}, // 一对字符中间有空格
+		{"00ffa", []byte{0x00, 0xff}, ErrOddHex},
+		{"0g", []byte{}, ErrInvalidHex},
+		{"00ffz", []byte{0x00, 0xff}, ErrInvalidHex},
+	}
+	for _, tt := range tests {
+		r := NewHexReader(strings.NewReader(tt.in))
+		got, err := io.ReadAll(r)
+		if err != tt.err || !bytes.Equal(got, tt.want) {
+			t.Errorf("%q: got (% x, %v), want (% x, %v)", tt.in, got, err, tt.want, tt.err)
+		}
+		// 错误会一直保留
+		if _, err := r.Read(make([]byte, 1)); err != tt.err {
+			t.Errorf("%q: got err %v on next Read, want %v", tt.in, err, tt.err)
+		}
+	}
+}
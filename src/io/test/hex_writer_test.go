@@ -0,0 +1,96 @@
+package test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"testing"
+)
+
+const hexChunk = 1024
+
+// hexWriter 把写入的每个字节编码成两个小写的十六进制字符后写到 w。
+// 每次最多编码 hexChunk 个字节，编码用的缓存大小固定，不随 p 的大小增长。
+// 功能与 hex.NewEncoder 相同，多了一个什么也不做的 Close，可以用在需要 io.WriteCloser 的地方。
+type hexWriter struct {
+	w   io.Writer
+	buf [2 * hexChunk]byte
+}
+
+func NewHexWriter(w io.Writer) io.WriteCloser {
+	return &hexWriter{w: w}
+}
+
+// Write 返回的 n 是已经完整写出编码结果的原始字节数
+func (h *hexWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > hexChunk {
+			chunk = chunk[:hexChunk]
+		}
+		encoded := hex.Encode(h.buf[:], chunk)
+		m, err := h.w.Write(h.buf[:encoded])
+		n += m / 2
+		if err != nil {
+			return n, err
+		}
+		p = p[len(chunk):]
+	}
+	return n, nil
+}
+
+func (h *hexWriter) Close() error {
+	return nil
+}
+
+func TestHexWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewHexWriter(&buf)
+	n, err := w.Write([]byte{0x00, 0xff, 0xab})
+	if err != nil || n != 3 {
+		t.Fatalf("got (%d, %v), want (3, nil)", n, err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "00ffab" {
+		t.Errorf("got %q, want %q", buf.String(), "00ffab")
+	}
+}
+
+func TestHexWriterLarge(t *testing.T) {
+	// 超过 hexChunk 的数据分多次编码，结果与一次编码相同
+	data := bytes.Repeat([]byte("Don't panic."), 500)
+	var buf bytes.Buffer
+	if _, err := io.Copy(NewHexWriter(&buf), bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if want := hex.EncodeToString(data); buf.String() != want {
+		t.Errorf("got %d bytes, want %d bytes of %s...", buf.Len(), len(want), want[:16])
+	}
+}
+
+// hex.Dump 需要先拿到全部数据，返回的字符串还包含偏移和 ASCII 列，大小约为原始数据的 4 倍；
+// hexWriter 边写边编码，只用固定大小的缓存。
+//
+//	go test -run NONE -bench HexWriter -benchmem
+func BenchmarkHexWriter(b *testing.B) {
+	data := bytes.Repeat([]byte("Clear is better than clever."), 1<<10)
+
+	b.Run("HexWriter", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			w := NewHexWriter(io.Discard)
+			w.Write(data)
+			w.Close()
+		}
+	})
+	b.Run("Dump", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			io.WriteString(io.Discard, hex.Dump(data))
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var ErrInterrupted = errors.New("interruptible reader: interrupted")
+
+// InterruptibleReader 的 Read 可以被其他 goroutine 调用 Interrupt 打断：
+// 正在阻塞的 Read 立即返回 ErrInterrupted，之后的 Read 也都返回 ErrInterrupted。
+// 与 boundedReader 一样，底层的 Read 在单独的 goroutine 中进行，
+// 被打断时它仍在等待，返回后读到的数据会被丢弃。
+type InterruptibleReader struct {
+	r    io.Reader
+	once sync.Once
+	stop chan struct{}
+}
+
+func NewInterruptibleReader(r io.Reader) *InterruptibleReader {
+	return &InterruptibleReader{r: r, stop: make(chan struct{})}
+}
+
+func (ir *InterruptibleReader) Read(p []byte) (int, error) {
+	select {
+	case <-ir.stop:
+		return 0, ErrInterrupted
+	default:
+	}
+
+	ch := make(chan readResult, 1)
+	go func() {
+		buf := make([]byte, len(p))
+		n, err := ir.r.Read(buf)
+		ch <- readResult{buf[:n], err}
+	}()
+
+	select {
+	case res := <-ch:
+		return copy(p, res.buf), res.err
+	case <-ir.stop:
+		return 0, ErrInterrupted
+	}
+}
+
+// Interrupt 可以调用多次。底层的 Reader 实现了 io.Closer 时（如网络连接、管道）同时关闭它，
+// 让阻塞在底层 Read 上的 goroutine 也能尽快退出，返回的是 Close 的错误
+func (ir *InterruptibleReader) Interrupt() error {
+	var err error
+	ir.once.Do(func() {
+		close(ir.stop)
+		if c, ok := ir.r.(io.Closer); ok {
+			err = c.Close()
+		}
+	})
+	return err
+}
+
+func TestInterruptibleReader(t *testing.T) {
+	src := &slowReader{r: strings.NewReader("Don't panic"), delay: time.Second}
+	r := NewInterruptibleReader(src)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := r.Read(make([]byte, 16))
+		done <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond) // 等待 Read 阻塞
+	start := time.Now()
+	r.Interrupt()
+	select {
+	case err := <-done:
+		if err != ErrInterrupted {
+			t.Errorf("got err %v, want %v", err, ErrInterrupted)
+		}
+		if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+			t.Errorf("Read returned %v after Interrupt, want < 50ms", elapsed)
+		}
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Read did not return within 50ms after Interrupt")
+	}
+
+	// 之后的 Read 也返回 ErrInterrupted
+	if _, err := r.Read(make([]byte, 16)); err != ErrInterrupted {
+		t.Errorf("got err %v, want %v", err, ErrInterrupted)
+	}
+}
+
+func TestInterruptibleReaderPipe(t *testing.T) {
+	// 底层是 io.PipeReader，Interrupt 会关闭它，阻塞的底层 Read 返回 io.ErrClosedPipe
+	pr, pw := io.Pipe()
+	r := NewInterruptibleReader(pr)
+
+	go func() {
+		pw.Write([]byte("Cgo is not Go"))
+	}()
+	p := make([]byte, 32)
+	n, err := r.Read(p)
+	if err != nil || string(p[:n]) != "Cgo is not Go" {
+		t.Fatalf("got (%q, %v)", p[:n], err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		r.Interrupt()
+	}()
+	if _, err := r.Read(p); err != ErrInterrupted {
+		t.Errorf("got err %v, want %v", err, ErrInterrupted)
+	}
+	if _, err := pw.Write([]byte("x")); err != io.ErrClosedPipe {
+		t.Errorf("got err %v writing after Interrupt, want %v", err, io.ErrClosedPipe)
+	}
+}
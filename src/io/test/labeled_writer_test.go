@@ -0,0 +1,106 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+// labeledWriter 在每一行的开头插入 "[时间] label: "，时间使用 UTC。
+// 每次 Write 把前缀和数据拼好之后只调用一次底层的 Write，前缀不会和它后面的数据分开写出，
+// 多个 labeledWriter 共享同一个输出时（如 os.Stderr）不会出现前缀与内容错开的情况。
+type labeledWriter struct {
+	w         io.Writer
+	label     string
+	lineStart bool // 下一个字节是否是一行的开头
+	buf       []byte
+
+	now func() time.Time // 测试时可以替换成模拟的时钟
+}
+
+func NewLabeledWriter(w io.Writer, label string) io.Writer {
+	return &labeledWriter{w: w, label: label, lineStart: true, now: time.Now}
+}
+
+func (l *labeledWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	prefix := "[" + l.now().UTC().Format("2006-01-02T15:04:05Z") + "] " + l.label + ": "
+
+	buf := l.buf[:0]
+	lineStart := l.lineStart
+	for rest := p; len(rest) > 0; {
+		if lineStart {
+			buf = append(buf, prefix...)
+		}
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			buf = append(buf, rest...)
+			lineStart = false
+			break
+		}
+		buf = append(buf, rest[:i+1]...)
+		rest = rest[i+1:]
+		lineStart = true
+	}
+	l.buf = buf
+
+	// 写出失败时无法知道 p 中有多少字节已经写出去，返回 0，状态也保持不变
+	if _, err := l.w.Write(buf); err != nil {
+		return 0, err
+	}
+	l.lineStart = lineStart
+	return len(p), nil
+}
+
+// writeRecorder 记录每次 Write 收到的数据
+type writeRecorder struct {
+	writes []string
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func TestLabeledWriter(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2023, 6, 1, 8, 30, 0, 0, time.UTC)}
+	rec := new(writeRecorder)
+	w := NewLabeledWriter(rec, "api")
+	w.(*labeledWriter).now = clock.Now
+
+	io.WriteString(w, "server started\nlistening on :8080\n")
+	clock.Advance(time.Second)
+	// 一行分两次写入：第二次写入时不是行首，不插入前缀
+	io.WriteString(w, "request ")
+	io.WriteString(w, "handled\nbye")
+
+	want := []string{
+		"[2023-06-01T08:30:00Z] api: server started\n[2023-06-01T08:30:00Z] api: listening on :8080\n",
+		"[2023-06-01T08:30:01Z] api: request ",
+		"handled\n[2023-06-01T08:30:01Z] api: bye",
+	}
+	if len(rec.writes) != len(want) {
+		t.Fatalf("got %d writes %q, want %d", len(rec.writes), rec.writes, len(want))
+	}
+	for i := range want {
+		if rec.writes[i] != want[i] {
+			t.Errorf("write %d: got %q, want %q", i, rec.writes[i], want[i])
+		}
+	}
+}
+
+func TestLabeledWriterLocalTime(t *testing.T) {
+	// 非 UTC 的时间也按 UTC 输出
+	loc := time.FixedZone("CST", 8*3600)
+	var buf bytes.Buffer
+	w := NewLabeledWriter(&buf, "db")
+	w.(*labeledWriter).now = func() time.Time { return time.Date(2023, 6, 1, 16, 30, 0, 0, loc) }
+
+	io.WriteString(w, "connected\n")
+	if want := "[2023-06-01T08:30:00Z] db: connected\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
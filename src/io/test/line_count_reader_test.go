@@ -0,0 +1,50 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// LineCountReader 统计读取过的数据中 '\n' 的个数。
+// 最后一行没有以 '\n' 结尾时不会被计入。
+type LineCountReader struct {
+	r     io.Reader
+	lines int64
+}
+
+func NewLineCountReader(r io.Reader) *LineCountReader {
+	return &LineCountReader{r: r}
+}
+
+func (l *LineCountReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.lines += int64(bytes.Count(p[:n], []byte{'\n'}))
+	return n, err
+}
+
+func (l *LineCountReader) Lines() int64 {
+	return l.lines
+}
+
+func TestLineCountReader(t *testing.T) {
+	var b strings.Builder
+	for i := 1; i <= 1000; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+
+	r := NewLineCountReader(strings.NewReader(b.String()))
+	io.Copy(io.Discard, r)
+	if r.Lines() != 1000 {
+		t.Errorf("got %d lines, want 1000", r.Lines())
+	}
+
+	// 去掉最后的换行，流在最后一行的中间结束
+	r = NewLineCountReader(strings.NewReader(strings.TrimSuffix(b.String(), "\n")))
+	io.Copy(io.Discard, r)
+	if r.Lines() != 999 {
+		t.Errorf("got %d lines, want 999", r.Lines())
+	}
+}
@@ -0,0 +1,114 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+var errNotMarked = errors.New("mark reader: Reset without Mark")
+
+// MarkReader 支持 Mark 和 Reset：Mark 之后读取的数据会被记录到 buf 中，
+// Reset 回到 Mark 的位置，之后的 Read 先回放 buf 中的数据，再继续读取底层 Reader。
+// 再次调用 Mark 会把标记移动到当前位置，之前记录的数据被丢弃。
+type MarkReader struct {
+	r      io.Reader
+	buf    []byte // 从 Mark 的位置开始记录的数据
+	pos    int    // 当前读取位置在 buf 中的偏移量
+	marked bool
+}
+
+func NewMarkReader(r io.Reader) *MarkReader {
+	return &MarkReader{r: r}
+}
+
+func (m *MarkReader) Read(p []byte) (int, error) {
+	if m.pos < len(m.buf) {
+		n := copy(p, m.buf[m.pos:])
+		m.pos += n
+		return n, nil
+	}
+
+	n, err := m.r.Read(p)
+	if m.marked {
+		m.buf = append(m.buf, p[:n]...)
+		m.pos = len(m.buf)
+	}
+	return n, err
+}
+
+// Mark 在当前位置设置标记
+func (m *MarkReader) Mark() {
+	// 回放中途设置标记时，保留还没有回放的数据
+	m.buf = append(m.buf[:0], m.buf[m.pos:]...)
+	m.pos = 0
+	m.marked = true
+}
+
+// Reset 回到最近一次 Mark 的位置
+func (m *MarkReader) Reset() error {
+	if !m.marked {
+		return errNotMarked
+	}
+	m.pos = 0
+	return nil
+}
+
+func TestMarkReader(t *testing.T) {
+	r := NewMarkReader(strings.NewReader("Clear is better than clever"))
+
+	p := make([]byte, 5)
+	io.ReadFull(r, p)
+	r.Mark()
+
+	first := make([]byte, 10)
+	io.ReadFull(r, first)
+	if err := r.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	second := make([]byte, 10)
+	io.ReadFull(r, second)
+	if string(first) != string(second) || string(first) != " is better" {
+		t.Errorf("got %q and %q, want %q twice", first, second, " is better")
+	}
+
+	// Reset 之后可以继续读取标记之后的数据
+	rest, _ := io.ReadAll(r)
+	if string(rest) != " than clever" {
+		t.Errorf("got %q, want %q", rest, " than clever")
+	}
+}
+
+func TestMarkReaderNested(t *testing.T) {
+	r := NewMarkReader(strings.NewReader("0123456789"))
+	p := make([]byte, 3)
+
+	r.Mark()
+	io.ReadFull(r, p) // 012
+	r.Mark()
+	io.ReadFull(r, p) // 345
+	r.Reset()
+	io.ReadFull(r, p)
+	if string(p) != "345" {
+		t.Errorf("got %q after second Mark, want %q", p, "345")
+	}
+
+	// 回放到一半时设置标记
+	r.Reset()
+	io.ReadFull(r, p[:1]) // 3
+	r.Mark()
+	io.ReadFull(r, p) // 456
+	r.Reset()
+	io.ReadFull(r, p)
+	if string(p) != "456" {
+		t.Errorf("got %q after Mark during replay, want %q", p, "456")
+	}
+}
+
+func TestMarkReaderNotMarked(t *testing.T) {
+	r := NewMarkReader(strings.NewReader("Don't panic"))
+	if err := r.Reset(); err != errNotMarked {
+		t.Errorf("got err %v, want %v", err, errNotMarked)
+	}
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// MetricsSink 接收指标的观测值，可以用 prometheus 的 Histogram、Summary 等实现
+type MetricsSink interface {
+	Observe(label string, value float64)
+}
+
+type meteringReader struct {
+	r            io.Reader
+	sink         MetricsSink
+	bytesLabel   string
+	latencyLabel string
+}
+
+// NewMeteringReader 在每次 Read 之后向 sink 报告读到的字节数（bytes_read）和这次 Read 的耗时（read_latency_ns）。
+// labels 是成对的键和值，按 prometheus 的格式附加在指标名之后，
+// 如 labels 为 "source", "upload" 时指标名为 bytes_read{source="upload"}。
+func NewMeteringReader(r io.Reader, sink MetricsSink, labels ...string) io.Reader {
+	if len(labels)%2 != 0 {
+		panic("NewMeteringReader: labels must be key-value pairs")
+	}
+	suffix := ""
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels)/2)
+		for i := 0; i < len(labels); i += 2 {
+			pairs = append(pairs, labels[i]+"="+strconv.Quote(labels[i+1]))
+		}
+		suffix = "{" + strings.Join(pairs, ",") + "}"
+	}
+	return &meteringReader{
+		r:            r,
+		sink:         sink,
+		bytesLabel:   "bytes_read" + suffix,
+		latencyLabel: "read_latency_ns" + suffix,
+	}
+}
+
+func (m *meteringReader) Read(p []byte) (int, error) {
+	start := time.Now()
+	n, err := m.r.Read(p)
+	elapsed := time.Since(start)
+	m.sink.Observe(m.bytesLabel, float64(n))
+	m.sink.Observe(m.latencyLabel, float64(elapsed.Nanoseconds()))
+	return n, err
+}
+
+type observation struct {
+	label string
+	value float64
+}
+
+// mockSink 记录所有的观测值
+type mockSink struct {
+	observations []observation
+}
+
+func (s *mockSink) Observe(label string, value float64) {
+	s.observations = append(s.observations, observation{label, value})
+}
+
+func TestMeteringReader(t *testing.T) {
+	sink := new(mockSink)
+	src := &chunkReader{r: strings.NewReader("Clear is better than clever"), size: 10}
+	r := NewMeteringReader(src, sink, "source", "proverbs", "format", "text")
+
+	b, err := io.ReadAll(r)
+	if err != nil || string(b) != "Clear is better than clever" {
+		t.Fatalf("got (%q, %v)", b, err)
+	}
+
+	// 10 + 10 + 7 个字节，最后一次 Read 返回 0 和 io.EOF；每次 Read 两个观测值
+	const suffix = `{source="proverbs",format="text"}`
+	wantBytes := []float64{10, 10, 7, 0}
+	if len(sink.observations) != 2*len(wantBytes) {
+		t.Fatalf("got %d observations, want %d", len(sink.observations), 2*len(wantBytes))
+	}
+	for i, want := range wantBytes {
+		bytesObs, latencyObs := sink.observations[2*i], sink.observations[2*i+1]
+		if bytesObs.label != "bytes_read"+suffix || bytesObs.value != want {
+			t.Errorf("read %d: got %s = %v, want bytes_read%s = %v", i, bytesObs.label, bytesObs.value, suffix, want)
+		}
+		if latencyObs.label != "read_latency_ns"+suffix || latencyObs.value < 0 {
+			t.Errorf("read %d: got %s = %v", i, latencyObs.label, latencyObs.value)
+		}
+	}
+}
+
+func TestMeteringReaderNoLabels(t *testing.T) {
+	sink := new(mockSink)
+	io.ReadAll(NewMeteringReader(strings.NewReader("Don't panic"), sink))
+	if len(sink.observations) == 0 || sink.observations[0].label != "bytes_read" {
+		t.Errorf("got observations %v", sink.observations)
+	}
+}
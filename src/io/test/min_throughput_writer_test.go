@@ -0,0 +1,112 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+var ErrThroughputTooLow = errors.New("min throughput writer: throughput too low")
+
+type writeSample struct {
+	t time.Time
+	n int
+}
+
+// minThroughputWriter 在每次 Write 之后计算最近 sampleWindow 内的平均写入速度，
+// 低于 minBytesPerSecond 时返回 ErrThroughputTooLow，用来发现卡住或过慢的下游（如慢速的网络连接）。
+// 从第一次 Write 开始经过一个完整的窗口之后才开始检查，避免刚开始时样本太少误报。
+// 返回错误时数据已经写出，n 仍然是实际写出的字节数。
+type minThroughputWriter struct {
+	w       io.Writer
+	min     int64
+	window  time.Duration
+	start   time.Time
+	samples []writeSample
+}
+
+func NewMinThroughputWriter(w io.Writer, minBytesPerSecond int64, sampleWindow time.Duration) io.Writer {
+	return &minThroughputWriter{w: w, min: minBytesPerSecond, window: sampleWindow}
+}
+
+func (m *minThroughputWriter) Write(p []byte) (int, error) {
+	if m.start.IsZero() {
+		m.start = time.Now()
+	}
+	n, err := m.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	now := time.Now()
+	m.samples = append(m.samples, writeSample{now, n})
+	// 丢弃窗口之外的样本
+	cutoff := now.Add(-m.window)
+	i := 0
+	for i < len(m.samples) && m.samples[i].t.Before(cutoff) {
+		i++
+	}
+	m.samples = m.samples[i:]
+
+	if now.Sub(m.start) < m.window {
+		return n, nil
+	}
+	var total int64
+	for _, s := range m.samples {
+		total += int64(s.n)
+	}
+	if float64(total)/m.window.Seconds() < float64(m.min) {
+		return n, ErrThroughputTooLow
+	}
+	return n, nil
+}
+
+// sleepyWriter 每次 Write 之前先等待 delay，模拟慢速的下游
+type sleepyWriter struct {
+	delay time.Duration
+}
+
+func (w sleepyWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+	return len(p), nil
+}
+
+func TestMinThroughputWriter(t *testing.T) {
+	// 每 20ms 写 10 个字节，约 500 B/s，低于要求的 10000 B/s
+	w := NewMinThroughputWriter(sleepyWriter{20 * time.Millisecond}, 10000, 100*time.Millisecond)
+	p := make([]byte, 10)
+
+	start := time.Now()
+	for {
+		n, err := w.Write(p)
+		if n != len(p) {
+			t.Fatalf("got n = %d, want %d", n, len(p))
+		}
+		if err == ErrThroughputTooLow {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if time.Since(start) > time.Second {
+			t.Fatal("ErrThroughputTooLow was not returned")
+		}
+	}
+	// 窗口结束之前不会报错
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("got error after %v, want at least one window", elapsed)
+	}
+}
+
+func TestMinThroughputWriterFast(t *testing.T) {
+	w := NewMinThroughputWriter(io.Discard, 10000, 50*time.Millisecond)
+	p := make([]byte, 1024)
+	deadline := time.Now().Add(150 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if _, err := w.Write(p); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"testing"
+)
+
+// multiCloser 依次关闭所有的 Closer，即使前面的 Close 失败也会继续关闭后面的，
+// 所有错误通过 errors.Join 合并返回。
+// 只有第一次 Close 会真正执行，之后的调用是空操作并返回 nil。
+type multiCloser struct {
+	closers []io.Closer
+	once    sync.Once
+}
+
+func NewMultiCloser(closers ...io.Closer) io.Closer {
+	return &multiCloser{closers: closers}
+}
+
+func (m *multiCloser) Close() error {
+	var errs []error
+	m.once.Do(func() {
+		for _, c := range m.closers {
+			if err := c.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// closerFunc 让普通函数实现 io.Closer
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+func TestMultiCloser(t *testing.T) {
+	errA := errors.New("close a")
+	errC := errors.New("close c")
+
+	var called []string
+	closer := func(name string, err error) io.Closer {
+		return closerFunc(func() error {
+			called = append(called, name)
+			return err
+		})
+	}
+
+	c := NewMultiCloser(closer("a", errA), closer("b", nil), closer("c", errC))
+	err := c.Close()
+	if len(called) != 3 {
+		t.Errorf("got %d closers called %v, want 3", len(called), called)
+	}
+	if !errors.Is(err, errA) || !errors.Is(err, errC) {
+		t.Errorf("got err %v, want it to contain %v and %v", err, errA, errC)
+	}
+
+	// 第二次 Close 是空操作
+	if err := c.Close(); err != nil {
+		t.Errorf("second Close: got err %v, want nil", err)
+	}
+	if len(called) != 3 {
+		t.Errorf("second Close called closers again: %v", called)
+	}
+}
@@ -0,0 +1,107 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+var errPaddingClosed = errors.New("padding writer: write after Close")
+
+// paddingWriter 把每次 Write 的数据用 padByte 补齐到 blockSize 的整数倍再写出，
+// 底层的 Writer 收到的总是完整的块，适合要求按块对齐的格式（如 tar 的 512 字节记录、块加密）。
+// 每次 Write 都会补齐，不会留下不完整的块，所以 Close 不需要再写入任何数据，
+// 只在底层的 Writer 实现了 io.Closer 时关闭它。
+type paddingWriter struct {
+	w         io.Writer
+	blockSize int
+	padByte   byte
+	buf       []byte
+	closed    bool
+}
+
+func NewPaddingWriter(w io.Writer, blockSize int, padByte byte) io.WriteCloser {
+	if blockSize <= 0 {
+		panic("NewPaddingWriter: blockSize must be positive")
+	}
+	return &paddingWriter{w: w, blockSize: blockSize, padByte: padByte}
+}
+
+// Write 返回的 n 只计算 p 中的字节，不包括补齐的部分
+func (pw *paddingWriter) Write(p []byte) (int, error) {
+	if pw.closed {
+		return 0, errPaddingClosed
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	pad := (pw.blockSize - len(p)%pw.blockSize) % pw.blockSize
+	buf := append(pw.buf[:0], p...)
+	for i := 0; i < pad; i++ {
+		buf = append(buf, pw.padByte)
+	}
+	pw.buf = buf
+
+	n, err := pw.w.Write(buf)
+	if n > len(p) {
+		n = len(p)
+	}
+	return n, err
+}
+
+func (pw *paddingWriter) Close() error {
+	if pw.closed {
+		return nil
+	}
+	pw.closed = true
+	if c, ok := pw.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func TestPaddingWriter(t *testing.T) {
+	const blockSize = 8
+	for _, size := range []int{0, 1, blockSize - 1, blockSize, blockSize + 1} {
+		rec := new(writeRecorder)
+		w := NewPaddingWriter(rec, blockSize, 0)
+		p := bytes.Repeat([]byte{'x'}, size)
+		n, err := w.Write(p)
+		if err != nil || n != size {
+			t.Errorf("size %d: got (%d, %v)", size, n, err)
+		}
+		w.Close()
+
+		var total int
+		for _, s := range rec.writes {
+			if len(s)%blockSize != 0 {
+				t.Errorf("size %d: underlying write of %d bytes", size, len(s))
+			}
+			total += len(s)
+		}
+		want := (size + blockSize - 1) / blockSize * blockSize
+		if total != want {
+			t.Errorf("size %d: got %d bytes, want %d", size, total, want)
+		}
+		if size > 0 && !bytes.Equal([]byte(rec.writes[0][:size]), p) {
+			t.Errorf("size %d: got %q", size, rec.writes[0])
+		}
+	}
+}
+
+func TestPaddingWriterMultipleWrites(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewPaddingWriter(&buf, 4, '.')
+	io.WriteString(w, "Go")
+	io.WriteString(w, "Gopher")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Go..Gopher.."; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	if _, err := io.WriteString(w, "x"); err != errPaddingClosed {
+		t.Errorf("got err %v after Close, want %v", err, errPaddingClosed)
+	}
+}
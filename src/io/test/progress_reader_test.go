@@ -0,0 +1,181 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// progressReader 在后台每隔 interval 向 out 输出一行读取进度，
+// 读到 io.EOF 时停止定时输出，并写入最后一行 "done: X bytes"。
+// 如果一直没有读到 io.EOF，后台的 goroutine 不会退出。
+type progressReader struct {
+	r     io.Reader
+	total int64
+	n     atomic.Int64
+
+	mu   sync.Mutex // 保护 out，定时输出和 Read 可能同时写入
+	out  io.Writer
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewProgressReader(r io.Reader, total int64, out io.Writer, interval time.Duration) io.Reader {
+	ticker := time.NewTicker(interval)
+	return newProgressReader(r, total, out, ticker.C, ticker.Stop)
+}
+
+// newProgressReader 每从 tick 收到一次就输出一行进度，测试中可以手动发送 tick
+func newProgressReader(r io.Reader, total int64, out io.Writer, tick <-chan time.Time, stopTick func()) *progressReader {
+	p := &progressReader{
+		r:     r,
+		total: total,
+		out:   out,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go p.report(tick, stopTick)
+	return p
+}
+
+func (p *progressReader) report(tick <-chan time.Time, stopTick func()) {
+	defer close(p.done)
+	defer stopTick()
+
+	for {
+		select {
+		case <-tick:
+			n := p.n.Load()
+			p.mu.Lock()
+			if p.total > 0 {
+				fmt.Fprintf(p.out, "read %d of %d bytes (%d%%)\n", n, p.total, n*100/p.total)
+			} else {
+				fmt.Fprintf(p.out, "read %d bytes\n", n)
+			}
+			p.mu.Unlock()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.n.Add(int64(n))
+	if err == io.EOF {
+		p.finish()
+	}
+	return n, err
+}
+
+func (p *progressReader) finish() {
+	select {
+	case <-p.stop:
+		// 已经结束过了
+		return
+	default:
+	}
+	close(p.stop)
+	<-p.done
+
+	p.mu.Lock()
+	fmt.Fprintf(p.out, "done: %d bytes\n", p.n.Load())
+	p.mu.Unlock()
+}
+
+func TestProgressReader(t *testing.T) {
+	// 每 50ms 读出 10 个字节，一共 500ms
+	data := strings.Repeat("x", 100)
+	src := &slowReader{r: &chunkReader{r: strings.NewReader(data), size: 10}, delay: 50 * time.Millisecond}
+
+	var out bytes.Buffer
+	r := NewProgressReader(src, int64(len(data)), &out, 100*time.Millisecond)
+
+	start := time.Now()
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("read took %v, want < 1s", elapsed)
+	}
+
+	fmt.Print(out.String())
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if last := lines[len(lines)-1]; last != "done: 100 bytes" {
+		t.Errorf("got last line %q, want %q", last, "done: 100 bytes")
+	}
+	// 500ms 内每 100ms 一行，但机器负载高或者开启 -race 时行数不确定，
+	// 只检查至少有一行，准确的输出由 TestProgressReaderTicks 检查
+	if n := len(lines) - 1; n < 1 {
+		t.Errorf("got %d progress lines, want at least 1", n)
+	}
+	for _, l := range lines[:len(lines)-1] {
+		if !strings.HasPrefix(l, "read ") || !strings.HasSuffix(l, "%)") {
+			t.Errorf("malformed progress line %q", l)
+		}
+	}
+}
+
+// lineWriter 把每次 Write 的内容发送到 lines
+type lineWriter struct {
+	lines chan string
+}
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	w.lines <- string(p)
+	return len(p), nil
+}
+
+func TestProgressReaderTicks(t *testing.T) {
+	tick := make(chan time.Time)
+	out := lineWriter{make(chan string, 8)}
+	r := newProgressReader(&chunkReader{r: strings.NewReader(strings.Repeat("x", 100)), size: 10}, 100, out, tick, func() {})
+
+	p := make([]byte, 100)
+	for _, tt := range []struct {
+		n    int64
+		want string
+	}{
+		{30, "read 30 of 100 bytes (30%)\n"},
+		{70, "read 70 of 100 bytes (70%)\n"},
+	} {
+		// 每次读 10 个字节，读够 tt.n 个字节后手动发送一次 tick，收到输出之前不再读取
+		for r.n.Load() < tt.n {
+			r.Read(p)
+		}
+		tick <- time.Time{}
+		if got := <-out.lines; got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-out.lines; got != "done: 100 bytes\n" {
+		t.Errorf("got %q, want %q", got, "done: 100 bytes\n")
+	}
+	select {
+	case l := <-out.lines:
+		t.Errorf("got unexpected line %q", l)
+	default:
+	}
+}
+
+// chunkReader 每次 Read 最多返回 size 个字节
+type chunkReader struct {
+	r    io.Reader
+	size int
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	if len(p) > c.size {
+		p = p[:c.size]
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,127 @@
+package test
+
+import (
+	"io"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+type writeEvent struct {
+	t time.Time
+	n int
+}
+
+// RateCountingWriter 记录最近 window 时间内每次写入的大小和时间，
+// 用来计算滑动窗口内的写入速率。
+type RateCountingWriter struct {
+	w      io.Writer
+	window time.Duration
+	now    func() time.Time // 测试时可以替换成模拟的时钟
+
+	mu     sync.Mutex
+	events []writeEvent
+	bytes  int64 // events 中字节数的总和
+}
+
+func NewRateCountingWriter(w io.Writer, window time.Duration) *RateCountingWriter {
+	return &RateCountingWriter{w: w, window: window, now: time.Now}
+}
+
+func (r *RateCountingWriter) Write(p []byte) (int, error) {
+	n, err := r.w.Write(p)
+
+	r.mu.Lock()
+	now := r.now()
+	r.events = append(r.events, writeEvent{t: now, n: n})
+	r.bytes += int64(n)
+	r.expire(now)
+	r.mu.Unlock()
+	return n, err
+}
+
+// expire 丢弃窗口之外的记录，调用时需要持有 mu
+func (r *RateCountingWriter) expire(now time.Time) {
+	i := 0
+	for ; i < len(r.events) && now.Sub(r.events[i].t) > r.window; i++ {
+		r.bytes -= int64(r.events[i].n)
+	}
+	r.events = r.events[i:]
+}
+
+// RateBPS 返回最近 window 时间内的平均写入速率（字节/秒），可以和 Write 并发调用
+func (r *RateCountingWriter) RateBPS() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expire(r.now())
+	return float64(r.bytes) / r.window.Seconds()
+}
+
+// fakeClock 是一个手动推进的时钟
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.t = c.t.Add(d)
+	c.mu.Unlock()
+}
+
+func TestRateCountingWriter(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	w := NewRateCountingWriter(io.Discard, time.Second)
+	w.now = clock.Now
+
+	// 以 10 KB/s 的速率写入 3 秒：每 10ms 写 100 字节
+	p := make([]byte, 100)
+	for i := 0; i < 300; i++ {
+		clock.Advance(10 * time.Millisecond)
+		w.Write(p)
+	}
+	if rate := w.RateBPS(); math.Abs(rate-10000)/10000 > 0.05 {
+		t.Errorf("got rate %.0f B/s, want 10000 B/s ±5%%", rate)
+	}
+
+	// 速率降到 5 KB/s，一个窗口之后旧的记录全部过期
+	for i := 0; i < 200; i++ {
+		clock.Advance(10 * time.Millisecond)
+		w.Write(p[:50])
+	}
+	if rate := w.RateBPS(); math.Abs(rate-5000)/5000 > 0.05 {
+		t.Errorf("got rate %.0f B/s, want 5000 B/s ±5%%", rate)
+	}
+
+	// 没有新的写入，窗口过去之后速率为 0
+	clock.Advance(2 * time.Second)
+	if rate := w.RateBPS(); rate != 0 {
+		t.Errorf("got rate %.0f B/s after idle, want 0", rate)
+	}
+}
+
+func TestRateCountingWriterConcurrent(t *testing.T) {
+	w := NewRateCountingWriter(io.Discard, 100*time.Millisecond)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			w.Write(make([]byte, 10))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			w.RateBPS()
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,54 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+var ErrNegativeSize = errors.New("ReadAtAll: negative size")
+
+// ReadAtAll 从 off 开始读取 size 个字节，相当于随机访问版本的 io.ReadAll。
+// 与 io.ReadAll 不同，读取的长度事先已知，所以一次性分配好缓存，只调用一次 ReadAt。
+// 数据不够 size 个字节时返回读到的部分和 ReadAt 的错误（通常是 io.EOF）；
+// 读满 size 个字节时即使 ReadAt 返回 io.EOF 也视为成功。
+func ReadAtAll(r io.ReaderAt, off int64, size int64) ([]byte, error) {
+	if size < 0 {
+		return nil, ErrNegativeSize
+	}
+	buf := make([]byte, size)
+	n, err := r.ReadAt(buf, off)
+	if n == len(buf) && err == io.EOF {
+		err = nil
+	}
+	return buf[:n], err
+}
+
+func TestReadAtAll(t *testing.T) {
+	data := []byte("Clear is better than clever")
+	tests := []struct {
+		off, size int64
+	}{
+		{0, 0},
+		{0, 5},
+		{6, 2},
+		{0, int64(len(data))},     // 正好读到末尾
+		{21, 10},                  // 超出末尾
+		{int64(len(data)), 1},     // 从末尾开始
+		{int64(len(data)) + 5, 1}, // 从末尾之后开始
+	}
+	for _, tt := range tests {
+		got, err := ReadAtAll(bytes.NewReader(data), tt.off, tt.size)
+
+		want := make([]byte, tt.size)
+		n, wantErr := bytes.NewReader(data).ReadAt(want, tt.off)
+		if !bytes.Equal(got, want[:n]) || err != wantErr {
+			t.Errorf("ReadAtAll(%d, %d) = (%q, %v), want (%q, %v)", tt.off, tt.size, got, err, want[:n], wantErr)
+		}
+	}
+
+	if _, err := ReadAtAll(bytes.NewReader(data), 0, -1); err != ErrNegativeSize {
+		t.Errorf("got err %v, want %v", err, ErrNegativeSize)
+	}
+}
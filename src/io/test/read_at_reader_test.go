@@ -0,0 +1,83 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// ReadAtReader 把 io.ReaderAt 转换成带有读取位置的 io.Reader。
+// 位置保存在 ReadAtReader 自身中，多个 ReadAtReader 可以共享同一个 ReaderAt 而互不影响。
+type ReadAtReader struct {
+	r   io.ReaderAt
+	pos int64
+}
+
+func NewReadAtReader(r io.ReaderAt, pos int64) *ReadAtReader {
+	return &ReadAtReader{r: r, pos: pos}
+}
+
+func (r *ReadAtReader) Read(p []byte) (int, error) {
+	n, err := r.r.ReadAt(p, r.pos)
+	r.pos += int64(n)
+	if err == io.EOF && n > 0 {
+		// ReadAt 读满 p 时也可能返回 io.EOF，留到下一次 Read 再返回
+		err = nil
+	}
+	return n, err
+}
+
+// Seek 只有在 ReaderAt 提供 Size 方法时（如 strings.Reader、io.SectionReader）才支持 io.SeekEnd
+func (r *ReadAtReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	default:
+		return 0, errWhence
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		s, ok := r.r.(interface{ Size() int64 })
+		if !ok {
+			return 0, errors.New("Seek: size of ReaderAt unknown")
+		}
+		offset += s.Size()
+	}
+	if offset < 0 {
+		return 0, errOffset
+	}
+	r.pos = offset
+	return offset, nil
+}
+
+func TestReadAtReaderIndependent(t *testing.T) {
+	src := strings.NewReader("Clear is better than clever")
+	r1 := NewReadAtReader(src, 0)
+	r2 := NewReadAtReader(src, 16)
+
+	// 交替读取，两个 Reader 的位置互不影响
+	p1, p2 := make([]byte, 5), make([]byte, 4)
+	for _, want := range [][2]string{{"Clear", "than"}, {" is b", " cle"}} {
+		io.ReadFull(r1, p1)
+		io.ReadFull(r2, p2)
+		if string(p1) != want[0] || string(p2) != want[1] {
+			t.Errorf("got (%q, %q), want (%q, %q)", p1, p2, want[0], want[1])
+		}
+	}
+
+	rest, err := io.ReadAll(r2)
+	if err != nil || string(rest) != "ver" {
+		t.Errorf("got (%q, %v), want %q", rest, err, "ver")
+	}
+}
+
+func TestReadAtReaderSeek(t *testing.T) {
+	r := NewReadAtReader(strings.NewReader("Errors are values"), 0)
+	if off, err := r.Seek(-6, io.SeekEnd); err != nil || off != 11 {
+		t.Fatalf("got (%d, %v), want 11", off, err)
+	}
+	b, _ := io.ReadAll(r)
+	if string(b) != "values" {
+		t.Errorf("got %q, want %q", b, "values")
+	}
+}
@@ -0,0 +1,98 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+var (
+	ErrNoStart = errors.New("ReadBetween: start delimiter not found")
+	ErrNoEnd   = errors.New("ReadBetween: end delimiter not found")
+)
+
+// ReadBetween 跳过 start 之前的数据，返回 start 与匹配的 end 之间的内容（不包含两端的分隔符）。
+// 支持嵌套：内层的 start 和 end 成对出现，原样保留在返回的内容中，如 "[[a][b]]" 返回 "[a][b]"。
+// start 与 end 相同时（如引号）不支持嵌套，读到下一个分隔符就结束。
+// 与 ReadUntil 一样逐字节读取，r 的位置正好在匹配的 end 之后。
+func ReadBetween(r io.Reader, start, end byte) ([]byte, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = NewByteReader(r)
+	}
+
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			return nil, ErrNoStart
+		}
+		if err != nil {
+			return nil, err
+		}
+		if c == start {
+			break
+		}
+	}
+
+	var data []byte
+	depth := 1
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			return data, ErrNoEnd
+		}
+		if err != nil {
+			return data, err
+		}
+		switch c {
+		case end:
+			depth--
+			if depth == 0 {
+				return data, nil
+			}
+		case start:
+			depth++
+		}
+		data = append(data, c)
+	}
+}
+
+func TestReadBetween(t *testing.T) {
+	tests := []struct {
+		input, want string
+		err         error
+	}{
+		{"level=info [content] rest", "content", nil},
+		{"[]", "", nil},
+		{"[[a][b]]", "[a][b]", nil},
+		{"x[a[b[c]]d]y", "a[b[c]]d", nil},
+		{"no brackets here", "", ErrNoStart},
+		{"[unterminated [a]", "unterminated [a]", ErrNoEnd},
+	}
+	for _, tt := range tests {
+		got, err := ReadBetween(strings.NewReader(tt.input), '[', ']')
+		if string(got) != tt.want || err != tt.err {
+			t.Errorf("ReadBetween(%q) = (%q, %v), want (%q, %v)", tt.input, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestReadBetweenSequential(t *testing.T) {
+	// 逐个提取引号中的内容，r 的位置停在上一个结束的引号之后
+	r := onlyReader{strings.NewReader(`"a" "b c" "d"`)}
+	var got []string
+	for {
+		s, err := ReadBetween(r, '"', '"')
+		if err == ErrNoStart {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, string(s))
+	}
+	if strings.Join(got, "|") != "a|b c|d" {
+		t.Errorf("got %q", got)
+	}
+}
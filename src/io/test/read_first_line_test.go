@@ -0,0 +1,54 @@
+package test
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// ReadFirstLine 读取第一行，返回的内容不包含 '\n'。
+// 每次只读一个字节，读到 '\n' 立即停止，r 的位置正好在 '\n' 之后，剩下的数据可以继续从 r 读取。
+// bufio.Reader 会预读多余的数据，读完第一行之后 r 的位置就不确定了。
+// 逐字节读取的代价是每个字节一次 Read 调用，只适合读取很短的行（如协议的首行）。
+// 与 bufio.Reader.ReadString 一样，读到末尾也没有 '\n' 时返回已读到的数据和 io.EOF。
+func ReadFirstLine(r io.Reader) (string, error) {
+	var line []byte
+	var b [1]byte
+	for {
+		n, err := r.Read(b[:])
+		if n > 0 {
+			if b[0] == '\n' {
+				return string(line), nil
+			}
+			line = append(line, b[0])
+		}
+		if err != nil {
+			return string(line), err
+		}
+	}
+}
+
+func TestReadFirstLine(t *testing.T) {
+	tests := []struct {
+		name, input string
+		line, rest  string
+		err         error
+	}{
+		{"single line", "Clear is better than clever\n", "Clear is better than clever", "", nil},
+		{"multi line", "HELO example.com\nMAIL FROM:<a@b>\nDATA\n", "HELO example.com", "MAIL FROM:<a@b>\nDATA\n", nil},
+		{"no newline", "Don't panic", "Don't panic", "", io.EOF},
+		{"empty line", "\nCgo is not Go", "", "Cgo is not Go", nil},
+		{"empty", "", "", "", io.EOF},
+	}
+	for _, tt := range tests {
+		r := strings.NewReader(tt.input)
+		line, err := ReadFirstLine(r)
+		if line != tt.line || err != tt.err {
+			t.Errorf("%s: got (%q, %v), want (%q, %v)", tt.name, line, err, tt.line, tt.err)
+		}
+		rest, _ := io.ReadAll(r)
+		if string(rest) != tt.rest {
+			t.Errorf("%s: got rest %q, want %q", tt.name, rest, tt.rest)
+		}
+	}
+}
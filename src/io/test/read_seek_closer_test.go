@@ -0,0 +1,40 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// nopReadSeekCloser 和 io.NopCloser 类似，但保留了 Seek 方法。
+// io.NopCloser 返回的是 io.ReadCloser，即使传入的 Reader 支持 Seek 也无法再使用。
+type nopReadSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopReadSeekCloser) Close() error { return nil }
+
+// NewReadSeekCloser 返回读取 b 的 io.ReadSeekCloser，Close 是空操作
+func NewReadSeekCloser(b []byte) io.ReadSeekCloser {
+	return nopReadSeekCloser{bytes.NewReader(b)}
+}
+
+func TestReadSeekCloser(t *testing.T) {
+	rsc := NewReadSeekCloser([]byte("Errors are values"))
+
+	p := make([]byte, 6)
+	if _, err := io.ReadFull(rsc, p); err != nil || string(p) != "Errors" {
+		t.Errorf("got (%q, %v), want %q", p, err, "Errors")
+	}
+
+	if off, err := rsc.Seek(-6, io.SeekEnd); err != nil || off != 11 {
+		t.Errorf("got (%d, %v), want 11", off, err)
+	}
+	if _, err := io.ReadFull(rsc, p); err != nil || string(p) != "values" {
+		t.Errorf("got (%q, %v), want %q", p, err, "values")
+	}
+
+	if err := rsc.Close(); err != nil {
+		t.Errorf("got err %v, want nil", err)
+	}
+}
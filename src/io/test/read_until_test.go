@@ -0,0 +1,87 @@
+package test
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+// ReadUntil 逐字节读取，直到读到 delim 为止，返回的数据包含 delim。
+// r 实现了 io.ByteReader 时（如 bufio.Reader、strings.Reader）直接调用 ReadByte，
+// 否则用 NewByteReader 每次读一个字节，不会多读，两种情况下 r 的位置都正好在 delim 之后。
+// 与 io.ReadFull 一致：没有读到任何数据时返回 io.EOF，读到一部分数据但没有 delim 时返回 io.ErrUnexpectedEOF。
+func ReadUntil(r io.Reader, delim byte) ([]byte, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = NewByteReader(r)
+	}
+
+	var data []byte
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			if len(data) > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return data, err
+		}
+		if err != nil {
+			return data, err
+		}
+		data = append(data, c)
+		if c == delim {
+			return data, nil
+		}
+	}
+}
+
+func TestReadUntil(t *testing.T) {
+	tests := []struct {
+		name, input string
+		data, rest  string
+		err         error
+	}{
+		{"delim at 0", ";Clear is better than clever", ";", "Clear is better than clever", nil},
+		{"delim at end", "Don't panic;", "Don't panic;", "", nil},
+		{"delim in middle", "GET;/index.html", "GET;", "/index.html", nil},
+		{"only delim", ";", ";", "", nil},
+		{"no delim", "Cgo is not Go", "Cgo is not Go", "", io.ErrUnexpectedEOF},
+		{"empty", "", "", "", io.EOF},
+	}
+	for _, tt := range tests {
+		for _, wrap := range []struct {
+			name string
+			fn   func(io.Reader) io.Reader
+		}{
+			{"ByteReader", func(r io.Reader) io.Reader { return r }},
+			{"Reader", func(r io.Reader) io.Reader { return onlyReader{r} }},
+		} {
+			sr := strings.NewReader(tt.input)
+			data, err := ReadUntil(wrap.fn(sr), ';')
+			if string(data) != tt.data || err != tt.err {
+				t.Errorf("%s/%s: got (%q, %v), want (%q, %v)", tt.name, wrap.name, data, err, tt.data, tt.err)
+			}
+			rest, _ := io.ReadAll(sr)
+			if string(rest) != tt.rest {
+				t.Errorf("%s/%s: got rest %q, want %q", tt.name, wrap.name, rest, tt.rest)
+			}
+		}
+	}
+}
+
+func TestReadUntilBufio(t *testing.T) {
+	// bufio.Reader 实现了 io.ByteReader，可以连续读取多个字段
+	br := bufio.NewReader(strings.NewReader("a,bb,ccc"))
+	var got []string
+	for {
+		field, err := ReadUntil(br, ',')
+		got = append(got, string(field))
+		if err != nil {
+			break
+		}
+	}
+	if strings.Join(got, "|") != "a,|bb,|ccc" {
+		t.Errorf("got %q", got)
+	}
+}
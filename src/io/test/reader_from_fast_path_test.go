@@ -0,0 +1,109 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// accumWriter 把写入的数据累积在内存中，实现了 io.ReaderFrom。
+// io.Copy 发现目标实现了 ReaderFrom 时直接调用 ReadFrom，
+// 由 accumWriter 把数据直接读到自己的缓存里，不需要 io.Copy 的中间缓存和多一次复制。
+type accumWriter struct {
+	data []byte
+
+	readFromCalls int
+}
+
+func (a *accumWriter) Write(p []byte) (int, error) {
+	a.data = append(a.data, p...)
+	return len(p), nil
+}
+
+func (a *accumWriter) ReadFrom(r io.Reader) (int64, error) {
+	a.readFromCalls++
+	var total int64
+	for {
+		if cap(a.data)-len(a.data) < 512 {
+			// 空间不够时扩容，append 会按需分配更大的底层数组
+			a.data = append(a.data, make([]byte, 512)...)[:len(a.data)]
+		}
+		n, err := r.Read(a.data[len(a.data):cap(a.data)])
+		a.data = a.data[:len(a.data)+n]
+		total += int64(n)
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// SpyReader 记录每次 Read 传入的缓存长度。
+// 它只有 Read 方法，会隐藏源的 io.WriterTo：io.Copy 优先检查源的 WriterTo，
+// bytes.Buffer 直接作为源时走的是 WriteTo，ReadFrom 不会被调用。
+type SpyReader struct {
+	r     io.Reader
+	reads []int
+}
+
+func (s *SpyReader) Read(p []byte) (int, error) {
+	s.reads = append(s.reads, len(p))
+	return s.r.Read(p)
+}
+
+// onlyWriter 隐藏了 ReaderFrom，让 io.Copy 走通用的缓存拷贝路径
+type onlyWriter struct{ io.Writer }
+
+func TestReaderFromFastPath(t *testing.T) {
+	data := bytes.Repeat([]byte("Clear is better than clever. "), 4096)
+
+	dst := new(accumWriter)
+	spy := &SpyReader{r: bytes.NewBuffer(data)}
+	if _, err := io.Copy(dst, spy); err != nil {
+		t.Fatal(err)
+	}
+	if dst.readFromCalls != 1 {
+		t.Errorf("got %d ReadFrom calls, want 1", dst.readFromCalls)
+	}
+	if !bytes.Equal(dst.data, data) {
+		t.Error("copied data mismatch")
+	}
+	// Read 的缓存是 accumWriter 内部的空间，大小随着扩容变化，而不是固定的 32KiB
+	t.Logf("ReadFrom: %d reads, first sizes %v", len(spy.reads), spy.reads[:3])
+
+	// 隐藏 ReaderFrom 之后，io.Copy 每次都读到自己的 32KiB 缓存中
+	dst = new(accumWriter)
+	spy = &SpyReader{r: bytes.NewBuffer(data)}
+	io.Copy(onlyWriter{dst}, spy)
+	if dst.readFromCalls != 0 || spy.reads[0] != 32*1024 {
+		t.Errorf("got %d ReadFrom calls and reads %v", dst.readFromCalls, spy.reads)
+	}
+}
+
+// go test -run NONE -bench ReaderFromFastPath -benchmem
+// ReadFrom 路径每个字节只复制一次（bytes.Buffer -> accumWriter），
+// 通用路径要复制两次（bytes.Buffer -> io.Copy 的缓存 -> accumWriter），还要分配 32KiB 的缓存
+func BenchmarkReaderFromFastPath(b *testing.B) {
+	data := bytes.Repeat([]byte("Don't panic. "), 64*1024)
+
+	b.Run("ReadFrom", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		dst := new(accumWriter)
+		for i := 0; i < b.N; i++ {
+			dst.data = dst.data[:0]
+			io.Copy(dst, onlyReader{bytes.NewBuffer(data)})
+		}
+	})
+	b.Run("Write", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		dst := new(accumWriter)
+		for i := 0; i < b.N; i++ {
+			dst.data = dst.data[:0]
+			io.Copy(onlyWriter{dst}, onlyReader{bytes.NewBuffer(data)})
+		}
+	})
+}
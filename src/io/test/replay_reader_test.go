@@ -0,0 +1,84 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// ReplayReader 在读取的同时把数据记录到内存中，之后可以多次回放。
+type ReplayReader struct {
+	r   io.Reader
+	mu  sync.Mutex
+	buf []byte
+}
+
+func NewReplayReader(r io.Reader) *ReplayReader {
+	return &ReplayReader{r: r}
+}
+
+// Record 返回一个 Reader，从它读出的数据会被记录下来
+func (rr *ReplayReader) Record() io.Reader {
+	return io.TeeReader(rr.r, replayRecorder{rr})
+}
+
+// replayRecorder 把写入的数据追加到 ReplayReader 的记录中。
+// 不直接给 ReplayReader 定义 Write，否则任何调用者都可以把数据写进记录。
+type replayRecorder struct {
+	rr *ReplayReader
+}
+
+func (w replayRecorder) Write(p []byte) (int, error) {
+	rr := w.rr
+	rr.mu.Lock()
+	rr.buf = append(rr.buf, p...)
+	rr.mu.Unlock()
+	return len(p), nil
+}
+
+// Replay 返回一个读取当前已记录数据的 Reader，可以多次调用，彼此互不影响
+func (rr *ReplayReader) Replay() io.Reader {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	return bytes.NewReader(rr.buf[:len(rr.buf):len(rr.buf)])
+}
+
+func TestReplayReader(t *testing.T) {
+	src := make([]byte, 10<<10)
+	rand.New(rand.NewSource(1)).Read(src)
+
+	rr := NewReplayReader(bytes.NewReader(src))
+	got, err := io.ReadAll(rr.Record())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, src) {
+		t.Fatal("recorded stream differs from source")
+	}
+
+	var wg sync.WaitGroup
+	replays := make([][]byte, 2)
+	for i := range replays {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			replays[i], _ = io.ReadAll(rr.Replay())
+		}(i)
+	}
+	wg.Wait()
+
+	for i, b := range replays {
+		if !bytes.Equal(b, src) {
+			t.Errorf("replay %d differs from source", i)
+		}
+	}
+}
+
+func TestReplayReaderNotWriter(t *testing.T) {
+	var r any = NewReplayReader(bytes.NewReader(nil))
+	if _, ok := r.(io.Writer); ok {
+		t.Error("ReplayReader implements io.Writer; recorded data could be injected")
+	}
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+var ErrNotSeekable = errors.New("resettable reader: source does not implement io.Seeker")
+
+// ResettableReader 可以通过 Reset 回到数据的开头重新读取，要求底层的 Reader 实现了 io.Seeker。
+// 开头是 Seek(0, io.SeekStart) 的位置，而不是创建 ResettableReader 时的位置。
+type ResettableReader struct {
+	r io.ReadSeeker
+}
+
+func NewResettableReader(r io.Reader) (*ResettableReader, error) {
+	rs, ok := r.(io.ReadSeeker)
+	if !ok {
+		return nil, ErrNotSeekable
+	}
+	return &ResettableReader{r: rs}, nil
+}
+
+// NewResettableReaderFromBytes 基于 bytes.Reader，总是可以 Reset
+func NewResettableReaderFromBytes(b []byte) *ResettableReader {
+	return &ResettableReader{r: bytes.NewReader(b)}
+}
+
+func (r *ResettableReader) Read(p []byte) (int, error) {
+	return r.r.Read(p)
+}
+
+func (r *ResettableReader) Reset() error {
+	_, err := r.r.Seek(0, io.SeekStart)
+	return err
+}
+
+func TestResettableReader(t *testing.T) {
+	const data = "Clear is better than clever"
+	r, err := NewResettableReader(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := make([]byte, len(data)/2)
+	if _, err := io.ReadFull(r, p); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil || string(b) != data {
+		t.Errorf("got (%q, %v) after Reset, want %q", b, err, data)
+	}
+}
+
+func TestResettableReaderFromBytes(t *testing.T) {
+	r := NewResettableReaderFromBytes([]byte("Don't panic"))
+	for i := 0; i < 3; i++ {
+		b, _ := io.ReadAll(r)
+		if string(b) != "Don't panic" {
+			t.Errorf("read %d: got %q", i, b)
+		}
+		r.Reset()
+	}
+}
+
+func TestResettableReaderNotSeekable(t *testing.T) {
+	// io.LimitReader 返回的 *io.LimitedReader 没有 Seek 方法
+	_, err := NewResettableReader(io.LimitReader(strings.NewReader("Cgo is not Go"), 3))
+	if err != ErrNotSeekable {
+		t.Errorf("got err %v, want %v", err, ErrNotSeekable)
+	}
+}
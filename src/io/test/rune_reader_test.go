@@ -0,0 +1,113 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+var errInvalidUnreadRune = errors.New("rune reader: invalid use of UnreadRune")
+
+// runeReader 为任意 io.Reader 实现 io.RuneScanner。
+// buf 中最多预读 utf8.UTFMax 个字节，足够判断出一个完整的 rune；
+// 非法的 UTF-8 编码返回 utf8.RuneError，size 为 1，和 bufio.Reader 的行为一致。
+type runeReader struct {
+	r   io.Reader
+	buf [utf8.UTFMax]byte
+	n   int // buf 中预读的字节数
+	err error
+
+	last     rune
+	lastSize int  // 最近一次 ReadRune 的结果，-1 表示不能 UnreadRune
+	unread   bool // UnreadRune 之后，下一次 ReadRune 直接返回 last
+}
+
+func NewRuneReader(r io.Reader) io.RuneScanner {
+	return &runeReader{r: r, lastSize: -1}
+}
+
+func (rr *runeReader) ReadRune() (rune, int, error) {
+	if rr.unread {
+		rr.unread = false
+		return rr.last, rr.lastSize, nil
+	}
+	rr.lastSize = -1
+
+	for rr.n < len(rr.buf) && !utf8.FullRune(rr.buf[:rr.n]) && rr.err == nil {
+		var m int
+		m, rr.err = rr.r.Read(rr.buf[rr.n:])
+		rr.n += m
+	}
+	if rr.n == 0 {
+		err := rr.err
+		if err == nil {
+			err = io.ErrNoProgress
+		}
+		return 0, 0, err
+	}
+
+	r, size := utf8.DecodeRune(rr.buf[:rr.n])
+	rr.n = copy(rr.buf[:], rr.buf[size:rr.n])
+	rr.last, rr.lastSize = r, size
+	return r, size, nil
+}
+
+// UnreadRune 只能撤销最近一次成功的 ReadRune
+func (rr *runeReader) UnreadRune() error {
+	if rr.lastSize < 0 || rr.unread {
+		return errInvalidUnreadRune
+	}
+	rr.unread = true
+	return nil
+}
+
+func TestRuneReaderUnread(t *testing.T) {
+	const s = "Go语言，你好"
+	r := NewRuneReader(strings.NewReader(s))
+
+	var got []rune
+	for {
+		c, size, err := r.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.UnreadRune(); err != nil {
+			t.Fatal(err)
+		}
+		c2, size2, _ := r.ReadRune()
+		if c != c2 || size != size2 {
+			t.Errorf("got (%q, %d) after UnreadRune, want (%q, %d)", c2, size2, c, size)
+		}
+		got = append(got, c)
+	}
+	if string(got) != s {
+		t.Errorf("got %q, want %q", string(got), s)
+	}
+	if err := r.UnreadRune(); err != errInvalidUnreadRune {
+		t.Errorf("got err %v, want %v", err, errInvalidUnreadRune)
+	}
+}
+
+func TestRuneReaderInvalid(t *testing.T) {
+	// "\xe4\xbd" 是 "你" 被截断的编码，后面跟着 'A'
+	r := NewRuneReader(strings.NewReader("\xe4\xbdA"))
+	want := []struct {
+		r    rune
+		size int
+	}{
+		{utf8.RuneError, 1},
+		{utf8.RuneError, 1},
+		{'A', 1},
+	}
+	for _, w := range want {
+		c, size, err := r.ReadRune()
+		if err != nil || c != w.r || size != w.size {
+			t.Errorf("got (%q, %d, %v), want (%q, %d)", c, size, err, w.r, w.size)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// sequentialWriteAt 把 WriterAt 当作顺序写入的 Writer 使用，每次 Write 从上一次写完的位置开始。
+// offset 没有加锁保护，不能并发调用 Write；需要并发写入时直接使用 WriteAt 并自己分配偏移量。
+type sequentialWriteAt struct {
+	w      io.WriterAt
+	offset int64
+}
+
+func NewSequentialWriteAt(w io.WriterAt) io.Writer {
+	return &sequentialWriteAt{w: w}
+}
+
+func (s *sequentialWriteAt) Write(p []byte) (int, error) {
+	n, err := s.w.WriteAt(p, s.offset)
+	s.offset += int64(n)
+	return n, err
+}
+
+// bufferWriterAt 是用 bytes.Buffer 实现的 WriterAt，写到末尾之后时自动扩展，中间用 0 填充
+type bufferWriterAt struct {
+	buf bytes.Buffer
+}
+
+func (b *bufferWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errOffset
+	}
+	if end := int(off) + len(p); end > b.buf.Len() {
+		b.buf.Write(make([]byte, end-b.buf.Len()))
+	}
+	return copy(b.buf.Bytes()[off:], p), nil
+}
+
+func TestSequentialWriteAt(t *testing.T) {
+	wa := new(bufferWriterAt)
+	w := NewSequentialWriteAt(wa)
+
+	for _, s := range []string{"Clear is ", "better ", "than ", "clever"} {
+		if n, err := io.WriteString(w, s); n != len(s) || err != nil {
+			t.Fatalf("WriteString(%q) = (%d, %v)", s, n, err)
+		}
+	}
+	if got, want := wa.buf.String(), "Clear is better than clever"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// 与直接调用 WriteAt 混用：WriteAt 不影响 sequentialWriteAt 记录的偏移量
+	wa.WriteAt([]byte("CLEAR"), 0)
+	io.WriteString(w, "!")
+	if got, want := wa.buf.String(), "CLEAR is better than clever!"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSequentialWriteAtCopy(t *testing.T) {
+	data := bytes.Repeat([]byte("Don't panic. "), 1000)
+	wa := new(bufferWriterAt)
+	// 每次最多读 100 个字节，io.Copy 会分多次调用 Write，结果应该与原数据一致
+	src := &chunkReader{r: bytes.NewReader(data), size: 100}
+	if _, err := io.Copy(NewSequentialWriteAt(wa), src); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(wa.buf.Bytes(), data) {
+		t.Errorf("got %d bytes, want %d", wa.buf.Len(), len(data))
+	}
+}
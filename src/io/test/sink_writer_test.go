@@ -0,0 +1,106 @@
+package test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// SinkWriter 丢弃写入的数据，只统计写入次数和字节数，
+// 测试结束时通过 Verify 检查写入的字节数是否符合预期。
+type SinkWriter struct {
+	expected int64
+	n        int64 // 实际接收的字节数
+	writes   int   // Write 被调用的次数
+
+	// MaxWrite 大于 0 时，每次 Write 最多接收 MaxWrite 个字节，
+	// 用来模拟短写（n < len(p)），此时返回 io.ErrShortWrite
+	MaxWrite int
+}
+
+func NewSinkWriter(expected int64) *SinkWriter {
+	return &SinkWriter{expected: expected}
+}
+
+func (s *SinkWriter) Write(p []byte) (int, error) {
+	s.writes++
+	n := len(p)
+	if s.MaxWrite > 0 && n > s.MaxWrite {
+		n = s.MaxWrite
+	}
+	s.n += int64(n)
+	if n < len(p) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+// Written 返回实际接收的字节数
+func (s *SinkWriter) Written() int64 {
+	return s.n
+}
+
+// Writes 返回 Write 被调用的次数
+func (s *SinkWriter) Writes() int {
+	return s.writes
+}
+
+func (s *SinkWriter) Verify(t testing.TB) {
+	t.Helper()
+	if s.n != s.expected {
+		t.Errorf("sink writer: got %d bytes in %d writes, want %d bytes", s.n, s.writes, s.expected)
+	}
+}
+
+// recordingTB 记录 Errorf 的调用而不是让测试失败，用来测试 Verify 本身
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestSinkWriter(t *testing.T) {
+	s := NewSinkWriter(int64(len("Cgo is not Go")))
+	io.WriteString(s, "Cgo ")
+	io.WriteString(s, "is not Go")
+	if s.Writes() != 2 {
+		t.Errorf("got %d writes, want 2", s.Writes())
+	}
+	s.Verify(t)
+}
+
+func TestSinkWriterShortWrite(t *testing.T) {
+	s := NewSinkWriter(4)
+	s.MaxWrite = 4
+	n, err := io.WriteString(s, "Don't panic")
+	if n != 4 || err != io.ErrShortWrite {
+		t.Errorf("got (%d, %v), want (4, %v)", n, err, io.ErrShortWrite)
+	}
+	s.Verify(t)
+
+	// io.Copy 遇到短写会停止，只有被接收的字节才会计入
+	s = NewSinkWriter(4)
+	s.MaxWrite = 4
+	if _, err := io.Copy(s, strings.NewReader("Don't panic")); err != io.ErrShortWrite {
+		t.Errorf("got err %v, want %v", err, io.ErrShortWrite)
+	}
+	s.Verify(t)
+}
+
+func TestSinkWriterVerifyFails(t *testing.T) {
+	s := NewSinkWriter(100)
+	io.WriteString(s, "Errors are values")
+
+	rec := &recordingTB{TB: t}
+	s.Verify(rec)
+	if len(rec.errors) != 1 {
+		t.Fatalf("got %d errors, want 1", len(rec.errors))
+	}
+	fmt.Println(rec.errors[0])
+}
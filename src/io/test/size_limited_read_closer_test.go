@@ -0,0 +1,114 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+// SizeLimitExceededError 表示数据超过了限制。
+// Received 是发现超出时已经收到的字节数，底层的数据可能比它更多，
+// 但为了知道确切的总长度而读完整个流，正违背了限制大小的初衷。
+type SizeLimitExceededError struct {
+	Limit    int64
+	Received int64
+}
+
+func (e *SizeLimitExceededError) Error() string {
+	return fmt.Sprintf("size limit exceeded: received %d bytes, limit is %d", e.Received, e.Limit)
+}
+
+// sizeLimitedReadCloser 与 io.LimitReader 不同，数据超过 maxBytes 时不是返回 io.EOF，
+// 而是返回 *SizeLimitExceededError，调用者可以区分“数据读完了”和“数据太大”。
+// 超出限制的字节不会返回给调用者。
+type sizeLimitedReadCloser struct {
+	rc       io.ReadCloser
+	maxBytes int64
+	n        int64 // 已经收到的字节数
+	err      error
+}
+
+// maxBytes 为负数时按 0 处理
+func NewSizeLimitedReadCloser(rc io.ReadCloser, maxBytes int64) io.ReadCloser {
+	if maxBytes < 0 {
+		maxBytes = 0
+	}
+	return &sizeLimitedReadCloser{rc: rc, maxBytes: maxBytes}
+}
+
+func (s *sizeLimitedReadCloser) Read(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	// 多读一个字节，才能知道数据是不是正好 maxBytes 个。
+	// 不直接计算 maxBytes - n + 1，maxBytes 为 math.MaxInt64 时会溢出
+	if remain := s.maxBytes - s.n; remain < int64(len(p))-1 {
+		p = p[:remain+1]
+	}
+	n, err := s.rc.Read(p)
+	s.n += int64(n)
+	if s.n > s.maxBytes {
+		n -= int(s.n - s.maxBytes)
+		s.err = &SizeLimitExceededError{Limit: s.maxBytes, Received: s.n}
+		return n, s.err
+	}
+	return n, err
+}
+
+func (s *sizeLimitedReadCloser) Close() error {
+	return s.rc.Close()
+}
+
+func TestSizeLimitedReadCloser(t *testing.T) {
+	s := "Clear is better than clever"
+	for _, max := range []int64{int64(len(s)), int64(len(s)) + 10} {
+		b, err := io.ReadAll(NewSizeLimitedReadCloser(io.NopCloser(strings.NewReader(s)), max))
+		if err != nil || string(b) != s {
+			t.Errorf("max %d: got (%q, %v), want %q", max, b, err, s)
+		}
+	}
+}
+
+func TestSizeLimitedReadCloserExtremeLimits(t *testing.T) {
+	s := "Clear is better than clever"
+	b, err := io.ReadAll(NewSizeLimitedReadCloser(io.NopCloser(strings.NewReader(s)), math.MaxInt64))
+	if err != nil || string(b) != s {
+		t.Errorf("MaxInt64: got (%q, %v), want %q", b, err, s)
+	}
+
+	// 负数的限制按 0 处理，第一个字节就超出
+	for _, max := range []int64{-1, -100, math.MinInt64} {
+		b, err := io.ReadAll(NewSizeLimitedReadCloser(io.NopCloser(strings.NewReader(s)), max))
+		var limitErr *SizeLimitExceededError
+		if !errors.As(err, &limitErr) || limitErr.Limit != 0 || len(b) != 0 {
+			t.Errorf("max %d: got (%q, %v), want limit 0 exceeded", max, b, err)
+		}
+	}
+}
+
+func TestSizeLimitedReadCloserExceeded(t *testing.T) {
+	rc := NewSizeLimitedReadCloser(io.NopCloser(strings.NewReader("Clear is better than clever")), 5)
+	var dst bytes.Buffer
+	n, err := io.Copy(&dst, rc)
+
+	var limitErr *SizeLimitExceededError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("got err %v, want *SizeLimitExceededError", err)
+	}
+	if limitErr.Limit != 5 || limitErr.Received <= 5 {
+		t.Errorf("got %+v, want Limit 5 and Received > 5", limitErr)
+	}
+	if n != 5 || dst.String() != "Clear" {
+		t.Errorf("copied (%d, %q), want (5, %q)", n, dst.String(), "Clear")
+	}
+
+	// 包装之后仍然可以取出来
+	wrapped := fmt.Errorf("upload: %w", err)
+	if !errors.As(wrapped, &limitErr) {
+		t.Errorf("errors.As failed on wrapped error %v", wrapped)
+	}
+}
@@ -0,0 +1,98 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// sliceReader 依次读取 slices 中的每个切片，不需要先用 bytes.Join 把它们复制到一起。
+// 实现了 io.WriterTo，io.Copy 时直接把每个切片交给目标 Writer，数据完全不经过复制。
+type sliceReader struct {
+	slices [][]byte
+	off    int // 在 slices[0] 中已经读过的字节数，不修改调用者的 slices
+}
+
+func NewSliceReader(slices [][]byte) io.Reader {
+	return &sliceReader{slices: slices}
+}
+
+func (s *sliceReader) Read(p []byte) (int, error) {
+	// 跳过读完的和空的切片，避免返回 0, nil
+	for len(s.slices) > 0 && s.off == len(s.slices[0]) {
+		s.slices, s.off = s.slices[1:], 0
+	}
+	if len(s.slices) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, s.slices[0][s.off:])
+	s.off += n
+	return n, nil
+}
+
+func (s *sliceReader) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for len(s.slices) > 0 {
+		n, err := w.Write(s.slices[0][s.off:])
+		total += int64(n)
+		s.off += n
+		if err != nil {
+			return total, err
+		}
+		s.slices, s.off = s.slices[1:], 0
+	}
+	return total, nil
+}
+
+func TestSliceReader(t *testing.T) {
+	slices := [][]byte{[]byte("Clear "), {}, []byte("is "), []byte("better "), []byte("than clever")}
+	want := "Clear is better than clever"
+
+	// 缓存比一个切片小、比一个切片大、和第一个切片一样大
+	for _, size := range []int{2, 16, len("Clear ")} {
+		r := NewSliceReader(slices)
+		var got []byte
+		p := make([]byte, size)
+		for {
+			n, err := r.Read(p)
+			got = append(got, p[:n]...)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if string(got) != want {
+			t.Errorf("size %d: got %q, want %q", size, got, want)
+		}
+	}
+
+	// 同一组切片可以被多次读取
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, NewSliceReader(slices))
+	if err != nil || n != int64(len(want)) || buf.String() != want {
+		t.Errorf("io.Copy: got (%d, %v, %q), want %q", n, err, buf.String(), want)
+	}
+}
+
+// go test -run NONE -bench SliceReader -benchmem
+func BenchmarkSliceReader(b *testing.B) {
+	slices := make([][]byte, 256)
+	for i := range slices {
+		slices[i] = bytes.Repeat([]byte{byte(i)}, 4<<10)
+	}
+
+	b.Run("SliceReader", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			io.Copy(io.Discard, NewSliceReader(slices))
+		}
+	})
+	b.Run("Join", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			io.Copy(io.Discard, bytes.NewReader(bytes.Join(slices, nil)))
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// http.DetectContentType 最多只看前 512 个字节
+const sniffLen = 512
+
+// SniffingReader 在第一次 Read 时预读最多 512 个字节，用 http.DetectContentType 判断 MIME 类型，
+// 预读的数据之后照常返回，不会丢失。
+type SniffingReader struct {
+	r           io.Reader
+	buf         []byte // 预读的数据中还没被读走的部分
+	contentType string
+	sniffed     bool
+	err         error // 预读时遇到的错误，等 buf 读完之后再返回
+}
+
+func NewSniffingReader(r io.Reader) *SniffingReader {
+	return &SniffingReader{r: r}
+}
+
+func (s *SniffingReader) Read(p []byte) (int, error) {
+	if !s.sniffed {
+		s.sniff()
+	}
+	if len(s.buf) > 0 {
+		n := copy(p, s.buf)
+		s.buf = s.buf[n:]
+		return n, nil
+	}
+	if s.err != nil {
+		return 0, s.err
+	}
+	return s.r.Read(p)
+}
+
+func (s *SniffingReader) sniff() {
+	s.sniffed = true
+	buf := make([]byte, sniffLen)
+	n, err := io.ReadFull(s.r, buf)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	s.buf, s.err = buf[:n], err
+	s.contentType = http.DetectContentType(s.buf)
+}
+
+// ContentType 返回检测到的 MIME 类型，在第一次 Read 之前调用时返回空字符串
+func (s *SniffingReader) ContentType() string {
+	return s.contentType
+}
+
+func TestSniffingReader(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"png", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", "image/png"},
+		{"jpeg", "\xff\xd8\xff\xe0\x00\x10JFIF\x00", "image/jpeg"},
+		{"pdf", "%PDF-1.7\n%\xe2\xe3\xcf\xd3\n", "application/pdf"},
+		{"text", "Clear is better than clever\n", "text/plain; charset=utf-8"},
+		{"large text", strings.Repeat("Don't panic\n", 100), "text/plain; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		r := NewSniffingReader(strings.NewReader(tt.data))
+		if ct := r.ContentType(); ct != "" {
+			t.Errorf("%s: got %q before Read, want empty", tt.name, ct)
+		}
+
+		// 用很小的缓存读取，确认预读的数据全部被返回
+		var got []byte
+		p := make([]byte, 7)
+		for {
+			n, err := r.Read(p)
+			got = append(got, p[:n]...)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if ct := r.ContentType(); ct != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, ct, tt.want)
+		}
+		if string(got) != tt.data {
+			t.Errorf("%s: got %d bytes, want %d", tt.name, len(got), len(tt.data))
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type SortedWriterOption func(*sortedWriter)
+
+// SortedWriterDesc 让 SortedWriter 按降序输出
+func SortedWriterDesc() SortedWriterOption {
+	return func(s *sortedWriter) {
+		s.desc = true
+	}
+}
+
+// sortedWriter 把写入的所有行缓存在内存中，Close 时排序后一次性写入 w。
+// 所有数据在 Close 之前都保存在内存里，内存占用与写入的数据量成正比，
+// 不适合数据量很大的场景，这时应该使用外部排序。
+type sortedWriter struct {
+	w    io.Writer
+	buf  bytes.Buffer
+	desc bool
+}
+
+func NewSortedWriter(w io.Writer, opts ...SortedWriterOption) io.WriteCloser {
+	s := &sortedWriter{w: w}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *sortedWriter) Write(p []byte) (int, error) {
+	return s.buf.Write(p)
+}
+
+// Close 按字典序排序后写出所有行，每行以 '\n' 结尾，不会关闭 w
+func (s *sortedWriter) Close() error {
+	data := strings.TrimSuffix(s.buf.String(), "\n")
+	s.buf.Reset()
+	if data == "" {
+		return nil
+	}
+
+	lines := strings.Split(data, "\n")
+	if s.desc {
+		sort.Sort(sort.Reverse(sort.StringSlice(lines)))
+	} else {
+		sort.Strings(lines)
+	}
+
+	var out bytes.Buffer
+	for _, l := range lines {
+		out.WriteString(l)
+		out.WriteByte('\n')
+	}
+	_, err := out.WriteTo(s.w)
+	return err
+}
+
+func TestSortedWriter(t *testing.T) {
+	var lines []string
+	for i := 0; i < 100; i++ {
+		lines = append(lines, fmt.Sprintf("line %03d", i))
+	}
+	shuffled := append([]string(nil), lines...)
+	rand.New(rand.NewSource(1)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	var buf bytes.Buffer
+	w := NewSortedWriter(&buf)
+	for _, l := range shuffled {
+		io.WriteString(w, l+"\n")
+	}
+	if buf.Len() != 0 {
+		t.Fatal("data written before Close")
+	}
+	w.Close()
+
+	if want := strings.Join(lines, "\n") + "\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestSortedWriterDesc(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewSortedWriter(&buf, SortedWriterDesc())
+	// 最后一行没有换行也会被当作一行
+	io.WriteString(w, "b\nc\na")
+	w.Close()
+
+	if want := "c\nb\na\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
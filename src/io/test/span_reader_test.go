@@ -0,0 +1,95 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+// Span 记录一次 Read 读到的数据在流中的范围 [StartOffset, EndOffset) 和这次 Read 花费的时间
+type Span struct {
+	StartOffset int64
+	EndOffset   int64
+	Duration    time.Duration
+}
+
+// SpanReader 记录每次 Read 的范围，用来观察解码器（如 json.Decoder、gzip.Reader）
+// 是怎样分块读取数据的：每次读多少、有没有读到不需要的部分。
+type SpanReader struct {
+	r     io.Reader
+	off   int64
+	spans []Span
+}
+
+func NewSpanReader(r io.Reader) *SpanReader {
+	return &SpanReader{r: r}
+}
+
+func (s *SpanReader) Read(p []byte) (int, error) {
+	start := time.Now()
+	n, err := s.r.Read(p)
+	s.spans = append(s.spans, Span{
+		StartOffset: s.off,
+		EndOffset:   s.off + int64(n),
+		Duration:    time.Since(start),
+	})
+	s.off += int64(n)
+	return n, err
+}
+
+// Spans 返回目前为止记录的所有范围，包括没有读到数据的 Read（StartOffset == EndOffset）
+func (s *SpanReader) Spans() []Span {
+	return s.spans
+}
+
+func TestSpanReader(t *testing.T) {
+	f, err := os.Open("./span_reader.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 文件中是连续的多个 JSON 对象，一直解码到 io.EOF，解码器会读完整个文件
+	r := NewSpanReader(f)
+	dec := json.NewDecoder(r)
+	var count int
+	for {
+		var v struct {
+			ID      int    `json:"id"`
+			Proverb string `json:"proverb"`
+		}
+		if err := dec.Decode(&v); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+
+	spans := r.Spans()
+	for _, s := range spans {
+		fmt.Printf("[%4d, %4d) %v\n", s.StartOffset, s.EndOffset, s.Duration)
+	}
+
+	// 各个范围首尾相接，没有空隙也没有重叠，合起来正好是整个文件
+	var off int64
+	for i, s := range spans {
+		if s.StartOffset != off || s.EndOffset < s.StartOffset {
+			t.Errorf("span %d: got [%d, %d), want start %d", i, s.StartOffset, s.EndOffset, off)
+		}
+		off = s.EndOffset
+	}
+	if off != fi.Size() {
+		t.Errorf("spans cover %d bytes, want %d", off, fi.Size())
+	}
+	if count != 19 || len(spans) < 2 {
+		t.Errorf("got %d objects in %d reads", count, len(spans))
+	}
+}
@@ -0,0 +1,136 @@
+package test
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+const (
+	ansiText   = iota // 普通文本
+	ansiEsc           // 读到了 ESC
+	ansiCSI           // 读到了 ESC [，等待结束字符
+	ansiOSC           // 读到了 ESC ]，等待 BEL 或 ESC \
+	ansiOSCEsc        // OSC 中读到了 ESC
+)
+
+// stripAnsiReader 去掉终端输出中的 ANSI 转义序列，如颜色 "\x1b[31m"、光标移动 "\x1b[2;5H"。
+// 转义序列可能被拆分到两次 Read 中，所以用 state 记录解析到了哪一步。
+type stripAnsiReader struct {
+	r     io.Reader
+	state int
+}
+
+func NewStripAnsiReader(r io.Reader) io.Reader {
+	return &stripAnsiReader{r: r}
+}
+
+func (s *stripAnsiReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for {
+		n, err := s.r.Read(p)
+		n = s.strip(p[:n])
+		// 读到的数据全是转义序列时继续读，避免返回 0, nil
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+// strip 原地过滤 p，返回保留下来的字节数
+func (s *stripAnsiReader) strip(p []byte) int {
+	n := 0
+	for _, c := range p {
+		switch s.state {
+		case ansiText:
+			if c == 0x1b {
+				s.state = ansiEsc
+				continue
+			}
+			p[n] = c
+			n++
+		case ansiEsc:
+			switch c {
+			case '[':
+				s.state = ansiCSI
+			case ']':
+				s.state = ansiOSC
+			default:
+				// 两个字节的转义序列，如 ESC 7
+				s.state = ansiText
+			}
+		case ansiCSI:
+			// 参数和中间字符在 0x20-0x3f 之间，结束字符在 0x40-0x7e 之间
+			if c >= 0x40 && c <= 0x7e {
+				s.state = ansiText
+			}
+		case ansiOSC:
+			switch c {
+			case 0x07:
+				s.state = ansiText
+			case 0x1b:
+				s.state = ansiOSCEsc
+			}
+		case ansiOSCEsc:
+			if c == '\\' {
+				s.state = ansiText
+			} else {
+				s.state = ansiOSC
+			}
+		}
+	}
+	return n
+}
+
+func TestStripAnsiReader(t *testing.T) {
+	input := "\x1b[1;31mError:\x1b[0m file not found\n" +
+		"\x1b[2J\x1b[10;20HDon't panic\x1b]0;title\x07!\n"
+	want := "Error: file not found\nDon't panic!\n"
+
+	b, err := io.ReadAll(NewStripAnsiReader(strings.NewReader(input)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != want {
+		t.Errorf("got %q, want %q", b, want)
+	}
+
+	// 纯文本原样返回
+	b, _ = io.ReadAll(NewStripAnsiReader(strings.NewReader(want)))
+	if string(b) != want {
+		t.Errorf("got %q, want %q", b, want)
+	}
+}
+
+func TestStripAnsiReaderSplit(t *testing.T) {
+	input := "\x1b[38;5;208mGopher\x1b[0m"
+	// OneByteReader 每次只读一个字节，每个转义序列都会被拆分到多次 Read 中
+	b, err := io.ReadAll(NewStripAnsiReader(iotest.OneByteReader(strings.NewReader(input))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "Gopher" {
+		t.Errorf("got %q, want %q", b, "Gopher")
+	}
+}
+
+func TestStripAnsiReaderEmptyBuffer(t *testing.T) {
+	// strings.Reader 对空的 p 返回 0, nil，不能因此一直循环
+	r := NewStripAnsiReader(strings.NewReader("\x1b[0mGo"))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if n, err := r.Read(nil); n != 0 || err != nil {
+			t.Errorf("got (%d, %v), want (0, nil)", n, err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Read with empty buffer did not return")
+	}
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// stripPaddingReader 是 paddingWriter 的逆操作：按 blockSize 读取完整的块，去掉每块末尾的 padByte。
+// 内容本身以 padByte 结尾时无法与补齐的部分区分，所以 padByte 应该选内容中不会出现的字节（如文本中的 0）。
+// 数据的长度不是 blockSize 的整数倍时，最后一个不完整的块同样去掉末尾的 padByte 后返回，之后返回 io.ErrUnexpectedEOF。
+type stripPaddingReader struct {
+	r       io.Reader
+	padByte byte
+	block   []byte
+	pending []byte // 当前块中还没被读走的内容
+	err     error
+}
+
+func NewStripPaddingReader(r io.Reader, blockSize int, padByte byte) io.Reader {
+	if blockSize <= 0 {
+		panic("NewStripPaddingReader: blockSize must be positive")
+	}
+	return &stripPaddingReader{r: r, padByte: padByte, block: make([]byte, blockSize)}
+}
+
+func (s *stripPaddingReader) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 {
+		if s.err != nil {
+			return 0, s.err
+		}
+		n, err := io.ReadFull(s.r, s.block)
+		// 不能用 bytes.TrimRight：string(padByte) 会把 0x80 以上的字节转换成两个字节的 UTF-8 编码
+		for n > 0 && s.block[n-1] == s.padByte {
+			n--
+		}
+		s.pending = s.block[:n]
+		s.err = err
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+func TestStripPaddingReader(t *testing.T) {
+	const blockSize = 8
+	for _, size := range []int{1, 5, 7, 9, 15, 100} {
+		data := strings.Repeat("Go", size)[:size]
+
+		var buf bytes.Buffer
+		w := NewPaddingWriter(&buf, blockSize, 0)
+		io.WriteString(w, data)
+		w.Close()
+
+		got, err := io.ReadAll(NewStripPaddingReader(&buf, blockSize, 0))
+		if err != nil || string(got) != data {
+			t.Errorf("size %d: got (%q, %v), want %q", size, got, err, data)
+		}
+	}
+}
+
+func TestStripPaddingReaderHighPadByte(t *testing.T) {
+	for _, padByte := range []byte{0xff, 0x80} {
+		var buf bytes.Buffer
+		w := NewPaddingWriter(&buf, 4, padByte)
+		io.WriteString(w, "ab")
+		w.Close()
+
+		got, err := io.ReadAll(NewStripPaddingReader(&buf, 4, padByte))
+		if err != nil || string(got) != "ab" {
+			t.Errorf("padByte %#x: got (%q, %v), want %q", padByte, got, err, "ab")
+		}
+	}
+}
+
+func TestStripPaddingReaderMultipleWrites(t *testing.T) {
+	// 多次写入时每次都补齐，读出时各段内容依次拼接在一起
+	var buf bytes.Buffer
+	w := NewPaddingWriter(&buf, 4, '.')
+	for _, s := range []string{"Clear", " is", " better"} {
+		io.WriteString(w, s)
+	}
+	got, _ := io.ReadAll(NewStripPaddingReader(&buf, 4, '.'))
+	if string(got) != "Clear is better" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestStripPaddingReaderTruncated(t *testing.T) {
+	got, err := io.ReadAll(NewStripPaddingReader(strings.NewReader("ab..ef"), 4, '.'))
+	if string(got) != "abef" || err != io.ErrUnexpectedEOF {
+		t.Errorf("got (%q, %v), want (%q, %v)", got, err, "abef", io.ErrUnexpectedEOF)
+	}
+}
@@ -0,0 +1,98 @@
+package test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// PairStats 分别统计两个方向上发送的字节数
+type PairStats struct {
+	atob atomic.Int64
+	btoa atomic.Int64
+}
+
+func (s *PairStats) AtoB() int64 { return s.atob.Load() }
+
+func (s *PairStats) BtoA() int64 { return s.btoa.Load() }
+
+// EndpointRW 是双向管道的一端，Write 的数据从另一端 Read 出来
+type EndpointRW struct {
+	r    *io.PipeReader
+	w    *io.PipeWriter
+	sent *atomic.Int64
+}
+
+func (e *EndpointRW) Read(p []byte) (int, error) {
+	return e.r.Read(p)
+}
+
+func (e *EndpointRW) Write(p []byte) (int, error) {
+	n, err := e.w.Write(p)
+	e.sent.Add(int64(n))
+	return n, err
+}
+
+// Close 关闭写入方向，另一端读完数据后得到 io.EOF；同时关闭本端的读取方向
+func (e *EndpointRW) Close() error {
+	e.w.Close()
+	return e.r.Close()
+}
+
+// NewSyncedPair 用两个 io.Pipe 连接 a 和 b，用于进程内的双端通信。
+// 和 io.Pipe 一样没有缓存，Write 会阻塞到另一端把数据读走为止。
+func NewSyncedPair() (a, b *EndpointRW, stats *PairStats) {
+	stats = &PairStats{}
+	abr, abw := io.Pipe()
+	bar, baw := io.Pipe()
+	a = &EndpointRW{r: bar, w: abw, sent: &stats.atob}
+	b = &EndpointRW{r: abr, w: baw, sent: &stats.btoa}
+	return a, b, stats
+}
+
+func TestSyncedPair(t *testing.T) {
+	client, server, stats := NewSyncedPair()
+
+	// 服务端：每收到一行请求，返回 "echo: " 加上请求内容
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer server.Close()
+		s := bufio.NewScanner(server)
+		for s.Scan() {
+			fmt.Fprintf(server, "echo: %s\n", s.Text())
+		}
+	}()
+
+	requests := []string{"ping", "Errors are values", "Don't panic"}
+	replies := bufio.NewReader(client)
+	var sent, received int
+	for _, req := range requests {
+		n, _ := fmt.Fprintf(client, "%s\n", req)
+		sent += n
+		resp, err := replies.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		received += len(resp)
+		if want := "echo: " + req + "\n"; resp != want {
+			t.Errorf("got %q, want %q", resp, want)
+		}
+	}
+	client.Close()
+	// 等服务端退出，保证最后一次 Write 已经计入统计
+	<-done
+
+	if stats.AtoB() != int64(sent) {
+		t.Errorf("AtoB: got %d, want %d", stats.AtoB(), sent)
+	}
+	if stats.BtoA() != int64(received) {
+		t.Errorf("BtoA: got %d, want %d", stats.BtoA(), received)
+	}
+	if want := len(strings.Join(requests, "")) + len(requests)*len("echo: \n"); received != want {
+		t.Errorf("received %d bytes, want %d", received, want)
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+// NewTempFileReadWriteSeeker 返回一个基于临时文件的 io.ReadWriteSeeker，
+// 数据量可能超过可用内存、不适合放进 bytes.Buffer 时使用。
+// 用完之后调用返回的 cleanup 关闭并删除临时文件，多次调用也没有问题。
+func NewTempFileReadWriteSeeker() (io.ReadWriteSeeker, func(), error) {
+	f, err := os.CreateTemp("", "rws-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		f.Close()
+		os.Remove(f.Name())
+	}
+	return f, cleanup, nil
+}
+
+func TestTempFileReadWriteSeeker(t *testing.T) {
+	rws, cleanup, err := NewTempFileReadWriteSeeker()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	const size = 1 << 20
+	data := bytes.Repeat([]byte("0123456789abcdef"), size/16)
+	if _, err := rws.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	// 在中间覆盖写入
+	patch := []byte("Don't panic")
+	if _, err := rws.Seek(size/2, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rws.Write(patch); err != nil {
+		t.Fatal(err)
+	}
+	copy(data[size/2:], patch)
+
+	if _, err := rws.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("got %d bytes, content mismatch", len(got))
+	}
+
+	// cleanup 之后临时文件被删除
+	name := rws.(*os.File).Name()
+	cleanup()
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Errorf("temp file %s still exists: %v", name, err)
+	}
+}
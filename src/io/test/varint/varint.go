@@ -0,0 +1,54 @@
+// Package varint 实现 protobuf 使用的变长整数编码（base 128 varint）。
+//
+// 每个字节的低 7 位保存数据，从低位到高位依次排列；最高位为 1 表示后面还有字节。
+// 小于 128 的数只占一个字节，uint64 的最大值占 10 个字节。
+// 与 encoding/binary 中的 Uvarint 编码相同，这里直接基于 io.ByteReader 和 io.ByteWriter 实现。
+package varint
+
+import (
+	"errors"
+	"io"
+)
+
+// MaxLen 是 uint64 编码后的最大长度
+const MaxLen = 10
+
+var ErrVarIntOverflow = errors.New("varint: value overflows a 64-bit integer")
+
+// WriteVarInt 把 v 编码后逐字节写入 w
+func WriteVarInt(w io.ByteWriter, v uint64) error {
+	for v >= 0x80 {
+		if err := w.WriteByte(byte(v) | 0x80); err != nil {
+			return err
+		}
+		v >>= 7
+	}
+	return w.WriteByte(byte(v))
+}
+
+// ReadVarInt 从 r 中读取一个编码后的整数。
+// 一个字节都没有读到时返回 io.EOF，读到一部分后遇到 EOF 时返回 io.ErrUnexpectedEOF。
+// 超过 MaxLen 个字节仍未结束，或者第 10 个字节超出了 uint64 的范围时返回 ErrVarIntOverflow。
+func ReadVarInt(r io.ByteReader) (uint64, error) {
+	var v uint64
+	var shift uint
+	for i := 0; i < MaxLen; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			if i > 0 && err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return v, err
+		}
+		if b < 0x80 {
+			// 第 10 个字节只剩下 1 位可用
+			if i == MaxLen-1 && b > 1 {
+				return v, ErrVarIntOverflow
+			}
+			return v | uint64(b)<<shift, nil
+		}
+		v |= uint64(b&0x7f) << shift
+		shift += 7
+	}
+	return v, ErrVarIntOverflow
+}
@@ -0,0 +1,88 @@
+package varint
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestVarIntRoundTrip(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		want []byte // protobuf 文档中的编码
+	}{
+		{0, []byte{0x00}},
+		{1, []byte{0x01}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x80, 0x01}},
+		{150, []byte{0x96, 0x01}}, // protobuf 文档中的例子
+		{16383, []byte{0xff, 0x7f}},
+		{16384, []byte{0x80, 0x80, 0x01}},
+		{math.MaxInt64, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}},
+		{math.MaxUint64, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := WriteVarInt(&buf, tt.v); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), tt.want) {
+			t.Errorf("WriteVarInt(%d) = % x, want % x", tt.v, buf.Bytes(), tt.want)
+		}
+		// 与 encoding/binary 的编码一致
+		if std := binary.AppendUvarint(nil, tt.v); !bytes.Equal(buf.Bytes(), std) {
+			t.Errorf("WriteVarInt(%d) = % x, binary.AppendUvarint = % x", tt.v, buf.Bytes(), std)
+		}
+
+		v, err := ReadVarInt(&buf)
+		if err != nil || v != tt.v {
+			t.Errorf("ReadVarInt(% x) = (%d, %v), want %d", tt.want, v, err, tt.v)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("ReadVarInt(% x) left %d bytes unread", tt.want, buf.Len())
+		}
+	}
+}
+
+func TestVarIntStream(t *testing.T) {
+	// 多个整数连续写入同一个流，依次读出
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	values := []uint64{3, 270, 86942, 0, math.MaxUint32}
+	for _, v := range values {
+		WriteVarInt(w, v)
+	}
+	w.Flush()
+
+	r := bufio.NewReader(&buf)
+	for _, want := range values {
+		if v, err := ReadVarInt(r); err != nil || v != want {
+			t.Errorf("got (%d, %v), want %d", v, err, want)
+		}
+	}
+	if _, err := ReadVarInt(r); err != io.EOF {
+		t.Errorf("got err %v at end of stream, want %v", err, io.EOF)
+	}
+}
+
+func TestReadVarIntErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want error
+	}{
+		{"", io.EOF},
+		{"\x80", io.ErrUnexpectedEOF},
+		{"\xff\xff", io.ErrUnexpectedEOF},
+		{strings.Repeat("\xff", 10) + "\x01", ErrVarIntOverflow}, // 10 个字节都带有后续标记
+		{strings.Repeat("\xff", 9) + "\x02", ErrVarIntOverflow},  // 超过 64 位
+	}
+	for _, tt := range tests {
+		if _, err := ReadVarInt(strings.NewReader(tt.in)); err != tt.want {
+			t.Errorf("ReadVarInt(% x): got err %v, want %v", tt.in, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+var ErrWindowExpired = errors.New("windowed reader: window expired")
+
+// windowedReader 只在 [start, end) 时间窗口内允许读取：
+// 窗口开始之前 Read 阻塞等待，窗口结束之后 Read 返回 ErrWindowExpired。
+type windowedReader struct {
+	r          io.Reader
+	start, end time.Time
+
+	// 测试时可以替换成模拟的时钟
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+func NewWindowedReader(r io.Reader, windowStart, windowEnd time.Time) io.Reader {
+	return &windowedReader{
+		r:     r,
+		start: windowStart,
+		end:   windowEnd,
+		now:   time.Now,
+		sleep: time.Sleep,
+	}
+}
+
+func (w *windowedReader) Read(p []byte) (int, error) {
+	for {
+		now := w.now()
+		if !now.Before(w.end) {
+			return 0, ErrWindowExpired
+		}
+		if !now.Before(w.start) {
+			break
+		}
+		w.sleep(w.start.Sub(now))
+	}
+	return w.r.Read(p)
+}
+
+func TestWindowedReader(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	start := clock.Now().Add(time.Minute)
+	end := start.Add(time.Minute)
+
+	var slept time.Duration
+	r := NewWindowedReader(strings.NewReader("Cgo is not Go"), start, end).(*windowedReader)
+	r.now = clock.Now
+	r.sleep = func(d time.Duration) {
+		slept += d
+		clock.Advance(d)
+	}
+
+	// 窗口开始之前：等待到窗口开始再读取
+	p := make([]byte, 4)
+	n, err := r.Read(p)
+	if err != nil || string(p[:n]) != "Cgo " {
+		t.Errorf("got (%q, %v), want %q", p[:n], err, "Cgo ")
+	}
+	if slept != time.Minute {
+		t.Errorf("blocked for %v, want %v", slept, time.Minute)
+	}
+
+	// 窗口内：直接读取，不再等待
+	clock.Advance(30 * time.Second)
+	n, err = r.Read(p)
+	if err != nil || string(p[:n]) != "is n" || slept != time.Minute {
+		t.Errorf("got (%q, %v) after blocking %v", p[:n], err, slept)
+	}
+
+	// 窗口结束之后
+	clock.Advance(30 * time.Second)
+	if _, err := r.Read(p); err != ErrWindowExpired {
+		t.Errorf("got err %v, want %v", err, ErrWindowExpired)
+	}
+}
+
+func TestWindowedReaderRealClock(t *testing.T) {
+	start := time.Now().Add(20 * time.Millisecond)
+	r := NewWindowedReader(strings.NewReader("Don't panic"), start, start.Add(time.Second))
+
+	b, err := io.ReadAll(r)
+	if err != nil || string(b) != "Don't panic" {
+		t.Errorf("got (%q, %v)", b, err)
+	}
+	if time.Now().Before(start) {
+		t.Error("read before the window started")
+	}
+}
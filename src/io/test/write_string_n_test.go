@@ -0,0 +1,110 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+var ErrNegativeCount = errors.New("WriteStringN: negative count")
+
+// WriteStringN 把 s 写入 w 共 n 次，返回写入的总字节数。
+// w 实现了 io.StringWriter 时直接调用 WriteString，否则只把 s 转换成 []byte 一次，
+// 而不是像循环调用 io.WriteString 那样每次都做类型断言和转换。
+// 某次写入出错时返回已经写入的字节数和这个错误。
+func WriteStringN(w io.Writer, s string, n int) (int64, error) {
+	if n < 0 {
+		return 0, ErrNegativeCount
+	}
+	sw, ok := w.(io.StringWriter)
+	var b []byte
+	if !ok {
+		b = []byte(s)
+	}
+
+	var total int64
+	for i := 0; i < n; i++ {
+		var m int
+		var err error
+		if ok {
+			m, err = sw.WriteString(s)
+		} else {
+			m, err = w.Write(b)
+		}
+		total += int64(m)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func TestWriteStringN(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := WriteStringN(&buf, "Go ", 3)
+	if err != nil || n != 9 || buf.String() != "Go Go Go " {
+		t.Errorf("got (%d, %v, %q)", n, err, buf.String())
+	}
+
+	buf.Reset()
+	n, err = WriteStringN(&buf, "Go ", 0)
+	if err != nil || n != 0 || buf.Len() != 0 {
+		t.Errorf("n == 0: got (%d, %v, %q)", n, err, buf.String())
+	}
+
+	if _, err := WriteStringN(&buf, "Go ", -1); err != ErrNegativeCount {
+		t.Errorf("got err %v, want %v", err, ErrNegativeCount)
+	}
+}
+
+// capWriter 总共只接收 n 个字节，之后的写入返回 io.ErrShortWrite
+type capWriter struct {
+	buf bytes.Buffer
+	n   int
+}
+
+func (c *capWriter) Write(p []byte) (int, error) {
+	if len(p) > c.n {
+		m, _ := c.buf.Write(p[:c.n])
+		c.n = 0
+		return m, io.ErrShortWrite
+	}
+	c.n -= len(p)
+	return c.buf.Write(p)
+}
+
+func TestWriteStringNError(t *testing.T) {
+	// 第 4 次写入时只写进去 1 个字节
+	w := &capWriter{n: 10}
+	n, err := WriteStringN(w, "abc", 5)
+	if err != io.ErrShortWrite || n != 10 || w.buf.String() != "abcabcabca" {
+		t.Errorf("got (%d, %v, %q), want (10, %v)", n, err, w.buf.String(), io.ErrShortWrite)
+	}
+}
+
+// go test -run NONE -bench WriteStringN -benchmem
+func BenchmarkWriteStringN(b *testing.B) {
+	s := strings.Repeat("x", 64)
+	var buf bytes.Buffer
+	buf.Grow(64 * 100)
+
+	b.Run("WriteStringN", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			WriteStringN(&buf, s, 100)
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		b.ReportAllocs()
+		var w io.Writer = &buf
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			for j := 0; j < 100; j++ {
+				io.WriteString(w, s)
+			}
+		}
+	})
+}
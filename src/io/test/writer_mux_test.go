@@ -0,0 +1,100 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// WriterMux 按 key 管理一组 Writer，第一次访问某个 key 时调用 factory 创建，
+// 之后返回缓存的 Writer，最后通过 CloseAll 统一关闭。
+type WriterMux struct {
+	mu      sync.Mutex
+	factory func(key string) (io.WriteCloser, error)
+	writers map[string]io.WriteCloser
+}
+
+func NewWriterMux(factory func(key string) (io.WriteCloser, error)) *WriterMux {
+	return &WriterMux{factory: factory, writers: make(map[string]io.WriteCloser)}
+}
+
+func (m *WriterMux) Writer(key string) (io.Writer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if w, ok := m.writers[key]; ok {
+		return w, nil
+	}
+	w, err := m.factory(key)
+	if err != nil {
+		return nil, err
+	}
+	m.writers[key] = w
+	return w, nil
+}
+
+// CloseAll 关闭所有创建过的 Writer，所有错误通过 errors.Join 合并返回
+func (m *WriterMux) CloseAll() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var errs []error
+	for key, w := range m.writers {
+		if err := w.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", key, err))
+		}
+		delete(m.writers, key)
+	}
+	return errors.Join(errs...)
+}
+
+func TestWriterMux(t *testing.T) {
+	dir := t.TempDir()
+	created := 0
+	mux := NewWriterMux(func(key string) (io.WriteCloser, error) {
+		created++
+		return os.Create(filepath.Join(dir, key+".log"))
+	})
+
+	keys := []string{"a", "b", "c", "d", "e"}
+	for i := 0; i < 3; i++ {
+		for _, k := range keys {
+			w, err := mux.Writer(k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fmt.Fprintf(w, "%s-%d\n", k, i)
+		}
+	}
+	if created != len(keys) {
+		t.Errorf("factory called %d times, want %d", created, len(keys))
+	}
+	if err := mux.CloseAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, k := range keys {
+		b, err := os.ReadFile(filepath.Join(dir, k+".log"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := fmt.Sprintf("%s-0\n%s-1\n%s-2\n", k, k, k)
+		if string(b) != want {
+			t.Errorf("%s: got %q, want %q", k, b, want)
+		}
+	}
+}
+
+func TestWriterMuxFactoryError(t *testing.T) {
+	errFactory := errors.New("factory failed")
+	mux := NewWriterMux(func(key string) (io.WriteCloser, error) {
+		return nil, errFactory
+	})
+	if _, err := mux.Writer("a"); err != errFactory {
+		t.Errorf("got err %v, want %v", err, errFactory)
+	}
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// blobReader 是内存中的一段数据，实现了 io.WriterTo。
+// io.Copy 发现源实现了 WriterTo 时直接调用 WriteTo，数据从 blob 一次写到目标，
+// 不需要先 Read 到中间缓存再 Write 出去。
+type blobReader struct {
+	data []byte
+	off  int
+
+	writeToCalls int
+}
+
+func (b *blobReader) Read(p []byte) (int, error) {
+	if b.off >= len(b.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, b.data[b.off:])
+	b.off += n
+	return n, nil
+}
+
+func (b *blobReader) WriteTo(w io.Writer) (int64, error) {
+	b.writeToCalls++
+	n, err := w.Write(b.data[b.off:])
+	b.off += n
+	return int64(n), err
+}
+
+// SpyWriter 记录每次 Write 收到的数据长度。
+// 它没有实现 io.ReaderFrom，io.Copy 不会走目标端的快速路径。
+type SpyWriter struct {
+	w      io.Writer
+	writes []int
+}
+
+func (s *SpyWriter) Write(p []byte) (int, error) {
+	s.writes = append(s.writes, len(p))
+	return s.w.Write(p)
+}
+
+// onlyReader 隐藏了 WriterTo，让 io.Copy 走通用的缓存拷贝路径
+type onlyReader struct{ io.Reader }
+
+func TestWriterToFastPath(t *testing.T) {
+	data := bytes.Repeat([]byte("Clear is better than clever. "), 4096) // 约 116KiB
+
+	var buf bytes.Buffer
+	spy := &SpyWriter{w: &buf}
+	src := &blobReader{data: data}
+	if _, err := io.Copy(spy, src); err != nil {
+		t.Fatal(err)
+	}
+	// WriteTo 被调用，数据只 Write 了一次
+	if src.writeToCalls != 1 || len(spy.writes) != 1 || spy.writes[0] != len(data) {
+		t.Errorf("got %d WriteTo calls and writes %v", src.writeToCalls, spy.writes)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Error("copied data mismatch")
+	}
+
+	// 隐藏 WriterTo 之后，io.Copy 使用 32KiB 的中间缓存分多次写入
+	buf.Reset()
+	spy = &SpyWriter{w: &buf}
+	src = &blobReader{data: data}
+	io.Copy(spy, onlyReader{src})
+	if src.writeToCalls != 0 || len(spy.writes) != 4 || spy.writes[0] != 32*1024 {
+		t.Errorf("got %d WriteTo calls and writes %v", src.writeToCalls, spy.writes)
+	}
+}
+
+// go test -run NONE -bench WriterToFastPath -benchmem
+// 通用路径每次 io.Copy 都要分配 32KiB 的缓存，再加上 onlyReader 装箱成接口的一次分配；
+// WriteTo 路径没有任何分配
+func BenchmarkWriterToFastPath(b *testing.B) {
+	data := bytes.Repeat([]byte("Don't panic. "), 1024)
+	spy := &SpyWriter{w: io.Discard}
+
+	b.Run("WriteTo", func(b *testing.B) {
+		b.ReportAllocs()
+		src := &blobReader{data: data}
+		for i := 0; i < b.N; i++ {
+			src.off = 0
+			spy.writes = spy.writes[:0]
+			io.Copy(spy, src)
+		}
+	})
+	b.Run("buffered", func(b *testing.B) {
+		b.ReportAllocs()
+		src := &blobReader{data: data}
+		for i := 0; i < b.N; i++ {
+			src.off = 0
+			spy.writes = spy.writes[:0]
+			io.Copy(spy, onlyReader{src})
+		}
+	})
+}
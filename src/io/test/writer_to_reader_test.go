@@ -0,0 +1,59 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// WriterToReader 把 io.WriterTo 转换成 io.Reader。
+// 在 goroutine 中执行 wt.WriteTo(pw)，写入管道的数据从返回的 pr 中读出；
+// WriteTo 返回后关闭管道，它返回的错误会传递给读取方，没有错误时读取方得到 io.EOF。
+// 读取方需要读到结束，否则 WriteTo 会一直阻塞在管道上。
+func WriterToReader(wt io.WriterTo) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := wt.WriteTo(pw)
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// writerToFunc 让普通函数实现 io.WriterTo
+type writerToFunc func(w io.Writer) (int64, error)
+
+func (f writerToFunc) WriteTo(w io.Writer) (int64, error) {
+	return f(w)
+}
+
+func TestWriterToReader(t *testing.T) {
+	data := strings.Repeat("Channels orchestrate mutexes serialize\n", 1000)
+	r := WriterToReader(bytes.NewBufferString(data))
+
+	// ReadAll 能够返回说明 WriteTo 结束后管道被关闭了
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != data {
+		t.Errorf("got %d bytes, want %d", len(b), len(data))
+	}
+}
+
+func TestWriterToReaderError(t *testing.T) {
+	errWrite := errors.New("write failed")
+	r := WriterToReader(writerToFunc(func(w io.Writer) (int64, error) {
+		n, _ := io.WriteString(w, "partial")
+		return int64(n), errWrite
+	}))
+
+	b, err := io.ReadAll(r)
+	if err != errWrite {
+		t.Errorf("got err %v, want %v", err, errWrite)
+	}
+	if string(b) != "partial" {
+		t.Errorf("got %q, want %q", b, "partial")
+	}
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// CallDepthLogger 在每次调用 Output 时额外跳过 extraDepth 层调用栈。
+// 把 log.Logger 包装在辅助函数中时，log.Lshortfile、log.Llongfile 记录的是辅助函数所在的位置，
+// 设置 extraDepth 为包装的层数后，记录的就是最外层调用者的位置。
+type CallDepthLogger struct {
+	l          *log.Logger
+	extraDepth int
+}
+
+func NewCallDepthLogger(w io.Writer, flags int, extraDepth int) *CallDepthLogger {
+	return &CallDepthLogger{l: log.New(w, "", flags), extraDepth: extraDepth}
+}
+
+// Output 与 log.Logger.Output 相同，calldepth 为 1 时表示 Output 的调用者
+func (l *CallDepthLogger) Output(calldepth int, s string) error {
+	return l.l.Output(calldepth+1+l.extraDepth, s)
+}
+
+func (l *CallDepthLogger) Print(v ...any) {
+	l.Output(2, fmt.Sprint(v...))
+}
+
+func (l *CallDepthLogger) Printf(format string, v ...any) {
+	l.Output(2, fmt.Sprintf(format, v...))
+}
+
+func (l *CallDepthLogger) Println(v ...any) {
+	l.Output(2, fmt.Sprintln(v...))
+}
+
+// 两层包装：logRequest -> logWithLevel -> CallDepthLogger
+func logRequest(l *CallDepthLogger, path string) {
+	logWithLevel(l, "INFO", "request "+path)
+}
+
+func logWithLevel(l *CallDepthLogger, level, msg string) {
+	l.Printf("[%s] %s", level, msg)
+}
+
+func TestCallDepthLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewCallDepthLogger(&buf, log.Lshortfile, 2)
+
+	_, _, line, _ := runtime.Caller(0)
+	logRequest(l, "/index.html")
+
+	want := fmt.Sprintf("call_depth_logger_test.go:%d: [INFO] request /index.html\n", line+1)
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	fmt.Print(buf.String())
+}
+
+func TestCallDepthLoggerWrongDepth(t *testing.T) {
+	// extraDepth 为 0 时记录的是 logWithLevel 中调用 Printf 的位置
+	var buf bytes.Buffer
+	l := NewCallDepthLogger(&buf, log.Lshortfile, 0)
+
+	_, _, line, _ := runtime.Caller(0)
+	logRequest(l, "/index.html")
+
+	if strings.Contains(buf.String(), fmt.Sprintf(":%d:", line+1)) {
+		t.Errorf("got %q, want the location inside logWithLevel", buf.String())
+	}
+
+	// 直接调用时 extraDepth 为 0 就是调用者的位置
+	buf.Reset()
+	_, _, line, _ = runtime.Caller(0)
+	l.Println("direct")
+	if want := fmt.Sprintf("call_depth_logger_test.go:%d: direct\n", line+1); buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,124 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedBuffer 用 sync.Mutex 保护 bytes.Buffer，单次 Write 是原子的
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// 自己拼日志时前缀和内容分两次写入，锁只能保证每次 Write 不被打断，
+// 两次 Write 之间其他 goroutine 仍然可以插进来，前缀和内容就会错开。
+func printWithPrefix(w io.Writer, prefix string, i int) {
+	io.WriteString(w, prefix)
+	// 模拟两次写入之间被调度出去（如格式化参数、分配内存时），让错开的情况更容易出现
+	runtime.Gosched()
+	fmt.Fprintf(w, "line %d\n", i)
+}
+
+var logLine = regexp.MustCompile(`^\[g\d{3}\] line \d+$`)
+
+// countMalformed 返回格式不正确（前缀和内容错开）的行数
+func countMalformed(b []byte) (lines, malformed int) {
+	for _, line := range bytes.Split(bytes.TrimSuffix(b, []byte("\n")), []byte("\n")) {
+		lines++
+		if !logLine.Match(line) {
+			malformed++
+		}
+	}
+	return
+}
+
+const (
+	logGoroutines = 100
+	logLines      = 1000
+)
+
+func runConcurrently(print func(prefix string, i int)) {
+	var wg sync.WaitGroup
+	wg.Add(logGoroutines)
+	for g := 0; g < logGoroutines; g++ {
+		go func(prefix string) {
+			defer wg.Done()
+			for i := 0; i < logLines; i++ {
+				print(prefix, i)
+			}
+		}(fmt.Sprintf("[g%03d] ", g))
+	}
+	wg.Wait()
+}
+
+// log.Logger 在 Output 中先加锁，再依次写入时间、前缀、文件位置和日志内容到内部的缓存，
+// 最后用一次 Write 输出整行，然后才解锁。格式化和写入都在同一把锁里完成，
+// 所以一行日志总是完整的，即使底层的 Writer 本身不是并发安全的。
+// 每个 goroutine 使用不同前缀时需要不同的 Logger，这时它们共享同一个 Writer，
+// 每行仍然只有一次 Write，lockedBuffer 的锁足以保证行不会交错。
+func TestLogConcurrencyComparison(t *testing.T) {
+	var mutexBuf lockedBuffer
+	start := time.Now()
+	runConcurrently(func(prefix string, i int) {
+		printWithPrefix(&mutexBuf, prefix, i)
+	})
+	mutexElapsed := time.Since(start)
+
+	var loggerBuf lockedBuffer
+	loggers := make(map[string]*log.Logger)
+	for g := 0; g < logGoroutines; g++ {
+		prefix := fmt.Sprintf("[g%03d] ", g)
+		loggers[prefix] = log.New(&loggerBuf, prefix, 0)
+	}
+	start = time.Now()
+	runConcurrently(func(prefix string, i int) {
+		loggers[prefix].Printf("line %d", i)
+	})
+	loggerElapsed := time.Since(start)
+
+	mutexLines, mutexBad := countMalformed(mutexBuf.buf.Bytes())
+	loggerLines, loggerBad := countMalformed(loggerBuf.buf.Bytes())
+	fmt.Printf("mutex + bytes.Buffer: %d lines, %d malformed, %v\n", mutexLines, mutexBad, mutexElapsed)
+	fmt.Printf("log.Logger:           %d lines, %d malformed, %v\n", loggerLines, loggerBad, loggerElapsed)
+
+	// 错开的情况取决于调度，不一定出现，这里只检查 log.Logger 的输出
+	if loggerLines != logGoroutines*logLines || loggerBad != 0 {
+		t.Errorf("log.Logger: got %d lines with %d malformed, want %d well-formed lines",
+			loggerLines, loggerBad, logGoroutines*logLines)
+	}
+}
+
+// go test -bench LogConcurrency -benchmem
+func BenchmarkLogConcurrency(b *testing.B) {
+	b.Run("mutex", func(b *testing.B) {
+		var buf lockedBuffer
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				printWithPrefix(&buf, "[g000] ", i)
+			}
+		})
+	})
+	b.Run("log.Logger", func(b *testing.B) {
+		var buf lockedBuffer
+		l := log.New(&buf, "[g000] ", 0)
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				l.Printf("line %d", i)
+			}
+		})
+	})
+}
@@ -0,0 +1,48 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+// io.MultiWriter 把每次 Write 依次写到所有的 Writer，log.Logger 每条日志只 Write 一次，
+// 所以每个目标收到的都是完整的行。
+// 生产环境中第二个目标通常是日志文件或远程日志服务的客户端：
+//
+//	f, err := os.OpenFile("app.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+//	log.SetOutput(io.MultiWriter(os.Stderr, f))
+//
+// MultiWriter 在某个目标出错时停止并返回错误，后面的目标收不到这条日志，而 log.Printf 会忽略这个错误；
+// 远程目标可能很慢或不可用，最好包装成带缓存、不会阻塞和报错的 Writer，放在最后面。
+func TestDualOutputLogger(t *testing.T) {
+	defer func(w io.Writer, flags int, prefix string) {
+		log.SetOutput(w)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+	}(log.Writer(), log.Flags(), log.Prefix())
+
+	var buf bytes.Buffer
+	log.SetOutput(io.MultiWriter(&buf, os.Stdout))
+	log.SetFlags(log.LstdFlags)
+	log.SetPrefix("[app] ")
+
+	msgs := []string{"server started", "listening on :8080", "shutting down"}
+	for i, msg := range msgs {
+		log.Printf("%d %s", i, msg)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(msgs) {
+		t.Fatalf("got %d lines, want %d", len(lines), len(msgs))
+	}
+	for i, msg := range msgs {
+		if !strings.HasPrefix(lines[i], "[app] ") || !strings.HasSuffix(lines[i], fmt.Sprintf("%d %s", i, msg)) {
+			t.Errorf("line %d: got %q", i, lines[i])
+		}
+	}
+}
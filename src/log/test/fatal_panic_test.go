@@ -0,0 +1,80 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// log.Fatalf 和 log.Panicf 都先把格式化后的消息写入日志，区别在于之后怎么结束：
+//   - Fatalf 调用 os.Exit(1)，进程立即退出，defer 不会执行，也无法 recover，
+//     所以只能在子进程中测试；库代码中不应该使用，退出与否应该交给 main 决定。
+//   - Panicf 以格式化后的消息（string 类型）调用 panic，defer 会执行，
+//     调用链上的 recover 可以捕获，没有被捕获时程序以 exit code 2 崩溃并打印调用栈。
+
+// TestFatalf_Exits 以环境变量为标记重新运行测试程序本身，只运行这一个测试，
+// 子进程中调用 log.Fatalf，父进程检查退出码和输出
+func TestFatalf_Exits(t *testing.T) {
+	if os.Getenv("LOG_TEST_FATALF") == "1" {
+		defer os.Stdout.WriteString("deferred") // 不会执行
+		log.SetFlags(0)
+		log.Fatalf("config %q not found", "app.yaml")
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFatalf_Exits$")
+	cmd.Env = append(os.Environ(), "LOG_TEST_FATALF=1")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("got err %v, want exit status 1", err)
+	}
+	if want := "config \"app.yaml\" not found\n"; stderr.String() != want {
+		t.Errorf("got stderr %q, want %q", stderr.String(), want)
+	}
+	if strings.Contains(stdout.String(), "deferred") {
+		t.Error("deferred function ran after log.Fatalf")
+	}
+}
+
+func TestPanicf_Panics(t *testing.T) {
+	defer func(w io.Writer, flags int) {
+		log.SetOutput(w)
+		log.SetFlags(flags)
+	}(log.Writer(), log.Flags())
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+
+	var deferred bool
+	v := func() (v any) {
+		defer func() { v = recover() }()
+		defer func() { deferred = true }()
+		log.Panicf("config %q not found", "app.yaml")
+		return nil
+	}()
+
+	msg, ok := v.(string)
+	if !ok {
+		t.Fatalf("got panic value %#v (%T), want string", v, v)
+	}
+	if want := `config "app.yaml" not found`; msg != want {
+		t.Errorf("got panic value %q, want %q", msg, want)
+	}
+	if !deferred {
+		t.Error("deferred function did not run after log.Panicf")
+	}
+	// panic 之前消息已经写入日志
+	if buf.String() != msg+"\n" {
+		t.Errorf("got log %q, want %q", buf.String(), msg+"\n")
+	}
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// PrefixMux 根据日志行开头的前缀把日志分发到不同的 Writer，可以作为 log.New 的输出。
+// log.Logger 每输出一条日志只调用一次 Write，所以每次 Write 都是完整的一行。
+// 多个前缀都匹配时选择最长的那个，都不匹配时写入默认的 Writer（没有设置时丢弃）。
+type PrefixMux struct {
+	mu       sync.RWMutex
+	prefixes map[string]io.Writer
+	def      io.Writer
+}
+
+func NewPrefixMux() *PrefixMux {
+	return &PrefixMux{prefixes: make(map[string]io.Writer)}
+}
+
+func (m *PrefixMux) Register(prefix string, w io.Writer) {
+	m.mu.Lock()
+	m.prefixes[prefix] = w
+	m.mu.Unlock()
+}
+
+func (m *PrefixMux) SetDefault(w io.Writer) {
+	m.mu.Lock()
+	m.def = w
+	m.mu.Unlock()
+}
+
+func (m *PrefixMux) Write(p []byte) (int, error) {
+	m.mu.RLock()
+	w, matched := m.def, ""
+	for prefix, pw := range m.prefixes {
+		if len(prefix) > len(matched) && bytes.HasPrefix(p, []byte(prefix)) {
+			w, matched = pw, prefix
+		}
+	}
+	m.mu.RUnlock()
+
+	if w == nil {
+		return len(p), nil
+	}
+	return w.Write(p)
+}
+
+func TestPrefixMux(t *testing.T) {
+	var stdout, stderr, other bytes.Buffer
+	mux := NewPrefixMux()
+	mux.Register("[ERROR]", &stderr)
+	mux.Register("[INFO]", &stdout)
+	mux.SetDefault(&other)
+
+	errLog := log.New(mux, "[ERROR] ", 0)
+	infoLog := log.New(mux, "[INFO] ", 0)
+	debugLog := log.New(mux, "[DEBUG] ", 0)
+
+	infoLog.Println("server started")
+	errLog.Println("connection refused")
+	infoLog.Println("request handled")
+	debugLog.Println("cache miss")
+
+	if want := "[INFO] server started\n[INFO] request handled\n"; stdout.String() != want {
+		t.Errorf("stdout: got %q, want %q", stdout.String(), want)
+	}
+	if want := "[ERROR] connection refused\n"; stderr.String() != want {
+		t.Errorf("stderr: got %q, want %q", stderr.String(), want)
+	}
+	if want := "[DEBUG] cache miss\n"; other.String() != want {
+		t.Errorf("default: got %q, want %q", other.String(), want)
+	}
+	if strings.Contains(stdout.String(), "[ERROR]") || strings.Contains(stderr.String(), "[INFO]") {
+		t.Error("log lines routed to the wrong writer")
+	}
+}
+
+func TestPrefixMuxLongestMatch(t *testing.T) {
+	var db, all bytes.Buffer
+	mux := NewPrefixMux()
+	mux.Register("[ERROR]", &all)
+	mux.Register("[ERROR][db]", &db)
+
+	log.New(mux, "[ERROR][db] ", 0).Println("deadlock")
+	log.New(mux, "[ERROR] ", 0).Println("timeout")
+
+	if db.String() != "[ERROR][db] deadlock\n" || all.String() != "[ERROR] timeout\n" {
+		t.Errorf("got db=%q all=%q", db.String(), all.String())
+	}
+}
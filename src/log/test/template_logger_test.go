@@ -0,0 +1,135 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"text/template"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+	return "UNKNOWN"
+}
+
+// LogEntry 是传给模板的数据
+type LogEntry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  map[string]any
+}
+
+// TemplateLogger 用 text/template 格式化每一条日志，模板的输出不以换行结尾时自动加上换行。
+// 与 log.Logger 一样，整行先格式化到缓存中，再在锁的保护下一次 Write 出去；
+// 模板执行出错时什么都不写。
+type TemplateLogger struct {
+	mu   sync.Mutex
+	w    io.Writer
+	tmpl *template.Template
+	buf  bytes.Buffer
+
+	now func() time.Time // 测试时可以替换成固定的时间
+}
+
+func NewTemplateLogger(w io.Writer, tmpl string) (*TemplateLogger, error) {
+	t, err := template.New("log").Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateLogger{w: w, tmpl: t, now: time.Now}, nil
+}
+
+func (l *TemplateLogger) Log(level Level, msg string, fields map[string]any) error {
+	entry := LogEntry{Time: l.now(), Level: level, Message: msg, Fields: fields}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buf.Reset()
+	if err := l.tmpl.Execute(&l.buf, entry); err != nil {
+		return err
+	}
+	if b := l.buf.Bytes(); len(b) == 0 || b[len(b)-1] != '\n' {
+		l.buf.WriteByte('\n')
+	}
+	_, err := l.w.Write(l.buf.Bytes())
+	return err
+}
+
+func TestTemplateLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := NewTemplateLogger(&buf, "{{.Time}} {{.Level}} {{.Message}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2023, 6, 1, 8, 30, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	l.Log(LevelInfo, "server started", nil)
+	l.Log(LevelError, "connection refused", nil)
+
+	want := "2023-06-01 08:30:00 +0000 UTC INFO server started\n" +
+		"2023-06-01 08:30:00 +0000 UTC ERROR connection refused\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestTemplateLoggerFields(t *testing.T) {
+	var buf bytes.Buffer
+	// 模板中可以调用方法、遍历字段，map 按键的顺序遍历
+	tmpl := `{{.Time.Format "15:04:05"}} [{{.Level}}] {{.Message}}{{range $k, $v := .Fields}} {{$k}}={{$v}}{{end}}`
+	l, err := NewTemplateLogger(&buf, tmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.now = func() time.Time { return time.Date(2023, 6, 1, 8, 30, 0, 0, time.UTC) }
+
+	if err := l.Log(LevelWarn, "slow request", map[string]any{"path": "/index.html", "ms": 1200}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "08:30:00 [WARN] slow request ms=1200 path=/index.html\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestTemplateLoggerErrors(t *testing.T) {
+	if _, err := NewTemplateLogger(io.Discard, "{{.Message"); err == nil {
+		t.Error("got nil error for invalid template")
+	}
+
+	// 语法正确，但 Message 是 string，没有 Name 字段，执行时才出错
+	var buf bytes.Buffer
+	l, err := NewTemplateLogger(&buf, "{{.Message.Name}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = l.Log(LevelInfo, "hello", nil)
+	if err == nil || !strings.Contains(err.Error(), "Name") {
+		t.Errorf("got err %v, want execution error", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("got %q written after error, want nothing", buf.String())
+	}
+}
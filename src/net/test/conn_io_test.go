@@ -0,0 +1,94 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+// timeoutConn 在每次 Read/Write 之前重新设置截止时间。
+// net.Conn 的 SetDeadline 设置的是绝对时间，超时之后的读写都会失败，
+// 每次操作前都往后推，就相当于给单次操作设置了超时。
+type timeoutConn struct {
+	net.Conn
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+}
+
+// NewTimeoutConn 给 conn 的每次读写设置超时，超时时间为 0 表示不超时
+func NewTimeoutConn(conn net.Conn, readTimeout, writeTimeout time.Duration) net.Conn {
+	return &timeoutConn{Conn: conn, readTimeout: readTimeout, writeTimeout: writeTimeout}
+}
+
+func (c *timeoutConn) Read(p []byte) (int, error) {
+	if c.readTimeout > 0 {
+		if err := c.Conn.SetReadDeadline(time.Now().Add(c.readTimeout)); err != nil {
+			return 0, err
+		}
+	}
+	return c.Conn.Read(p)
+}
+
+func (c *timeoutConn) Write(p []byte) (int, error) {
+	if c.writeTimeout > 0 {
+		if err := c.Conn.SetWriteDeadline(time.Now().Add(c.writeTimeout)); err != nil {
+			return 0, err
+		}
+	}
+	return c.Conn.Write(p)
+}
+
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout() && errors.Is(err, os.ErrDeadlineExceeded)
+}
+
+func TestTimeoutConnRead(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	conn := NewTimeoutConn(client, 20*time.Millisecond, 0)
+	p := make([]byte, 16)
+
+	// 对端没有写入数据，读取超时
+	if _, err := conn.Read(p); !isTimeout(err) {
+		t.Fatalf("got err %v, want timeout", err)
+	}
+
+	// 超时之后连接仍然可用，下一次 Read 会重新设置截止时间
+	go io.WriteString(server, "Don't panic")
+	n, err := conn.Read(p)
+	if err != nil || string(p[:n]) != "Don't panic" {
+		t.Errorf("got (%q, %v), want %q", p[:n], err, "Don't panic")
+	}
+}
+
+func TestTimeoutConnWrite(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	conn := NewTimeoutConn(client, 0, 20*time.Millisecond)
+
+	// net.Pipe 没有缓存，对端不读取时写入会超时
+	if _, err := io.WriteString(conn, "Cgo is not Go"); !isTimeout(err) {
+		t.Fatalf("got err %v, want timeout", err)
+	}
+
+	done := make(chan []byte)
+	go func() {
+		p := make([]byte, 16)
+		n, _ := server.Read(p)
+		done <- p[:n]
+	}()
+	if _, err := io.WriteString(conn, "Cgo is not Go"); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-done; string(got) != "Cgo is not Go" {
+		t.Errorf("got %q", got)
+	}
+}
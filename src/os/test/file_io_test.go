@@ -0,0 +1,121 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// *os.File 同时实现了 io.Reader、io.Writer、io.Seeker 和 io.Closer，
+// 下面的函数用不同的打开模式打开文件，只返回需要用到的接口。
+// 打开失败时必须显式返回 nil：直接 return os.Open(path) 会把 nil 的 *os.File 装进接口，
+// 得到的接口值不等于 nil。
+
+// OpenReadSeeker 以只读方式打开文件
+func OpenReadSeeker(path string) (io.ReadSeekCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// OpenWriteSeeker 以只写方式打开文件，文件不存在时创建，存在时清空。
+// 返回的值实际是 *os.File，用完之后需要断言为 io.Closer 并关闭。
+func OpenWriteSeeker(path string) (io.WriteSeeker, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// OpenReadWriter 以读写方式打开文件，文件不存在时创建
+func OpenReadWriter(path string) (io.ReadWriteCloser, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func TestFileSeek(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "proverbs.txt")
+	payload := []byte("Channels orchestrate mutexes serialize\nCgo is not Go\n")
+
+	ws, err := OpenWriteSeeker(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.(io.Closer).Close()
+
+	if _, err := ws.Write(payload); err != nil {
+		t.Fatal(err)
+	}
+	// 回到开头覆盖写入，文件长度不变
+	if _, err := ws.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ws.Write([]byte("CHANNELS")); err != nil {
+		t.Fatal(err)
+	}
+
+	rs, err := OpenReadSeeker(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rs.Close()
+
+	want := append([]byte("CHANNELS"), payload[len("CHANNELS"):]...)
+	got, err := io.ReadAll(rs)
+	if err != nil || !bytes.Equal(got, want) {
+		t.Errorf("got (%q, %v), want %q", got, err, want)
+	}
+
+	// 只读打开的文件也可以 Seek
+	if _, err := rs.Seek(-int64(len("Cgo is not Go\n")), io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := io.ReadAll(rs); string(got) != "Cgo is not Go\n" {
+		t.Errorf("got %q after Seek", got)
+	}
+}
+
+func TestFileReadWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rw.txt")
+	rw, err := OpenReadWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rw.Close()
+
+	io.WriteString(rw, "Errors are values")
+	// 读写共用同一个偏移量，写完之后需要 Seek 回开头才能读到数据
+	if b, _ := io.ReadAll(rw); len(b) != 0 {
+		t.Errorf("got %q without Seek, want nothing", b)
+	}
+	rw.(io.Seeker).Seek(0, io.SeekStart)
+	if b, _ := io.ReadAll(rw); string(b) != "Errors are values" {
+		t.Errorf("got %q", b)
+	}
+}
+
+func TestOpenErrorReturnsNil(t *testing.T) {
+	// 文件不存在，或者所在的目录不存在，三个函数都会失败
+	path := filepath.Join(t.TempDir(), "missing", "proverbs.txt")
+
+	rs, err := OpenReadSeeker(path)
+	if err == nil || rs != nil {
+		t.Errorf("OpenReadSeeker: got (%#v, %v), want (nil, error)", rs, err)
+	}
+	ws, err := OpenWriteSeeker(path)
+	if err == nil || ws != nil {
+		t.Errorf("OpenWriteSeeker: got (%#v, %v), want (nil, error)", ws, err)
+	}
+	rw, err := OpenReadWriter(path)
+	if err == nil || rw != nil {
+		t.Errorf("OpenReadWriter: got (%#v, %v), want (nil, error)", rw, err)
+	}
+}
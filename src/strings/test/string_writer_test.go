@@ -0,0 +1,121 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// StringWriter 是基于 strings.Builder 的 io.Writer，写完之后用 String 取得结果。
+// strings.Builder 的 String 直接把内部的 []byte 转换成 string，不会复制数据；
+// bytes.Buffer 的 String 每次都要分配内存并复制一份。
+type StringWriter struct {
+	b strings.Builder
+}
+
+func NewStringWriter() *StringWriter {
+	return new(StringWriter)
+}
+
+func (w *StringWriter) Write(p []byte) (int, error) {
+	return w.b.Write(p)
+}
+
+func (w *StringWriter) WriteString(s string) (int, error) {
+	return w.b.WriteString(s)
+}
+
+func (w *StringWriter) WriteRune(r rune) (int, error) {
+	return w.b.WriteRune(r)
+}
+
+func (w *StringWriter) String() string {
+	return w.b.String()
+}
+
+func (w *StringWriter) Len() int {
+	return w.b.Len()
+}
+
+// Reset 丢弃已经写入的数据。之前 String 返回的字符串仍然有效，
+// 因为 strings.Builder 的 Reset 会重新分配内存，而不是复用原来的 []byte
+func (w *StringWriter) Reset() {
+	w.b.Reset()
+}
+
+var (
+	_ io.Writer       = (*StringWriter)(nil)
+	_ io.StringWriter = (*StringWriter)(nil)
+)
+
+func TestStringWriter(t *testing.T) {
+	w := NewStringWriter()
+	fmt.Fprintf(w, "%s is ", "Clear")
+	io.WriteString(w, "better than clever")
+	w.WriteRune(' ')
+	w.WriteRune('✓')
+
+	want := "Clear is better than clever ✓"
+	if w.String() != want || w.Len() != len(want) {
+		t.Errorf("got (%q, %d), want (%q, %d)", w.String(), w.Len(), want, len(want))
+	}
+
+	s := w.String()
+	w.Reset()
+	w.WriteString("Don't panic")
+	if w.String() != "Don't panic" || s != want {
+		t.Errorf("got %q and %q after Reset", w.String(), s)
+	}
+}
+
+var benchLines = strings.Split(strings.Repeat("Clear is better than clever\n", 100), "\n")
+
+// go test -bench StringConversion -benchmem
+// 写入加转换的总开销：两者扩容的策略不同，分配次数相差不大，
+// 但 bytes.Buffer 最后的 String 还要复制一份完整的数据，B/op 明显更高
+func BenchmarkStringConversion(b *testing.B) {
+	b.Run("StringWriter", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			w := NewStringWriter()
+			for _, line := range benchLines {
+				w.WriteString(line)
+			}
+			_ = w.String()
+		}
+	})
+	b.Run("bytes.Buffer", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var w bytes.Buffer
+			for _, line := range benchLines {
+				w.WriteString(line)
+			}
+			_ = w.String()
+		}
+	})
+}
+
+// 只比较 String 本身：写入一次之后反复转换
+func BenchmarkString(b *testing.B) {
+	data := strings.Repeat("Don't panic\n", 1000)
+
+	b.Run("StringWriter", func(b *testing.B) {
+		w := NewStringWriter()
+		w.WriteString(data)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = w.String()
+		}
+	})
+	b.Run("bytes.Buffer", func(b *testing.B) {
+		var w bytes.Buffer
+		w.WriteString(data)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = w.String()
+		}
+	})
+}
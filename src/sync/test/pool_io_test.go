@@ -0,0 +1,106 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// sync 包不能导入 io（io 依赖 sync，会形成循环导入），所以示例放在这里而不是 sync 包中。
+
+const pooledBufSize = 32 << 10
+
+// NewBufferPool 返回一个提供 32KiB 缓存的 sync.Pool。
+// 池中保存的是 *[]byte 而不是 []byte：把切片放进 interface{} 需要分配一次内存，指针则不需要。
+func NewBufferPool() *sync.Pool {
+	return &sync.Pool{
+		New: func() any {
+			b := make([]byte, pooledBufSize)
+			return &b
+		},
+	}
+}
+
+// PooledCopier 从 sync.Pool 中获取拷贝用的缓存，用完之后放回去。
+// io.Copy 在 src 没有实现 io.WriterTo、dst 没有实现 io.ReaderFrom 时，每次都会分配 32KiB 的缓存。
+type PooledCopier struct {
+	pool *sync.Pool
+}
+
+func NewPooledCopier(pool *sync.Pool) *PooledCopier {
+	return &PooledCopier{pool: pool}
+}
+
+func (c *PooledCopier) Copy(dst io.Writer, src io.Reader) (int64, error) {
+	bp := c.pool.Get().(*[]byte)
+	defer c.pool.Put(bp)
+	return io.CopyBuffer(dst, src, *bp)
+}
+
+// onlyReader 和 onlyWriter 隐藏了 WriterTo/ReaderFrom 等可选接口，
+// 让拷贝必须经过缓存
+type onlyReader struct{ io.Reader }
+
+type onlyWriter struct{ io.Writer }
+
+func TestPooledCopier(t *testing.T) {
+	data := strings.Repeat("Channels orchestrate mutexes serialize\n", 10000)
+	c := NewPooledCopier(NewBufferPool())
+
+	var buf bytes.Buffer
+	n, err := c.Copy(onlyWriter{&buf}, onlyReader{strings.NewReader(data)})
+	if err != nil || n != int64(len(data)) || buf.String() != data {
+		t.Errorf("got (%d, %v), want %d bytes copied", n, err, len(data))
+	}
+}
+
+func TestPooledCopierAllocs(t *testing.T) {
+	if raceEnabled {
+		// 开启 race 检测时 sync.Pool 会随机丢弃 Put 进来的对象，Copy 可能重新分配缓存
+		t.Skip("sync.Pool drops items randomly under the race detector")
+	}
+	data := make([]byte, 1<<20)
+	r := bytes.NewReader(data)
+	var src io.Reader = onlyReader{r}
+	var dst io.Writer = onlyWriter{io.Discard}
+	c := NewPooledCopier(NewBufferPool())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		r.Reset(data)
+		c.Copy(dst, src)
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocs per Copy, want 0", allocs)
+	}
+}
+
+func BenchmarkIOCopy(b *testing.B) {
+	data := make([]byte, 1<<20)
+	r := bytes.NewReader(data)
+	var src io.Reader = onlyReader{r}
+	var dst io.Writer = onlyWriter{io.Discard}
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.Reset(data)
+		io.Copy(dst, src)
+	}
+}
+
+func BenchmarkPooledCopy(b *testing.B) {
+	data := make([]byte, 1<<20)
+	r := bytes.NewReader(data)
+	var src io.Reader = onlyReader{r}
+	var dst io.Writer = onlyWriter{io.Discard}
+	c := NewPooledCopier(NewBufferPool())
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.Reset(data)
+		c.Copy(dst, src)
+	}
+}